@@ -0,0 +1,114 @@
+package protocmp
+
+// editOp is a step of an edit script aligning two sequences x and y.
+type editOp byte
+
+const (
+	// editMatch aligns an element of x with an equal element of y.
+	editMatch editOp = iota
+	// editDelete is an element of x only.
+	editDelete
+	// editInsert is an element of y only.
+	editInsert
+)
+
+// align returns the shortest edit script turning the sequence x of length n
+// into the sequence y of length m, where eq reports whether x[i] equals y[j].
+// The common prefix and suffix are matched first and only the rest is aligned
+// with Myers' O(ND) algorithm, so that sequences differing in a few elements
+// are aligned in about linear time and space.
+func align(n, m int, eq func(i, j int) bool) []editOp {
+	prefix := 0
+	for prefix < n && prefix < m && eq(prefix, prefix) {
+		prefix++
+	}
+	suffix := 0
+	for suffix < n-prefix && suffix < m-prefix && eq(n-1-suffix, m-1-suffix) {
+		suffix++
+	}
+
+	ops := make([]editOp, 0, n+m-prefix-suffix)
+	for k := 0; k < prefix; k++ {
+		ops = append(ops, editMatch)
+	}
+	ops = append(ops, myers(n-prefix-suffix, m-prefix-suffix, func(i, j int) bool {
+		return eq(prefix+i, prefix+j)
+	})...)
+	for k := 0; k < suffix; k++ {
+		ops = append(ops, editMatch)
+	}
+	return ops
+}
+
+// myers returns the shortest edit script with Myers' O(ND) algorithm. It keeps
+// the furthest reaching x of every diagonal k for each edit distance d, which
+// takes O(D²) space, and traces the script back from them.
+func myers(n, m int, eq func(i, j int) bool) []editOp {
+	var trace [][]int
+	for d := 0; d <= n+m; d++ {
+		v := make([]int, 2*d+1)
+		for k := -d; k <= d; k += 2 {
+			var x int
+			switch {
+			case d == 0:
+				x = 0
+			case k == -d || (k != d && at(trace[d-1], d-1, k-1) < at(trace[d-1], d-1, k+1)):
+				x = at(trace[d-1], d-1, k+1)
+			default:
+				x = at(trace[d-1], d-1, k-1) + 1
+			}
+			y := x - k
+			for x < n && y < m && eq(x, y) {
+				x++
+				y++
+			}
+			v[k+d] = x
+			if x >= n && y >= m {
+				return traceBack(append(trace, v), n, m)
+			}
+		}
+		trace = append(trace, v)
+	}
+	return nil
+}
+
+// at returns the furthest reaching x of the diagonal k after d edits.
+func at(v []int, d, k int) int {
+	return v[k+d]
+}
+
+// traceBack builds the edit script ending at (n, m) from the furthest reaching
+// paths found by myers.
+func traceBack(trace [][]int, n, m int) []editOp {
+	var ops []editOp
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		prev := trace[d-1]
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && at(prev, d-1, k-1) < at(prev, d-1, k+1)) {
+			prevK = k + 1
+		}
+		prevX := at(prev, d-1, prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			ops = append(ops, editMatch)
+			x--
+			y--
+		}
+		if prevK == k+1 {
+			ops = append(ops, editInsert)
+		} else {
+			ops = append(ops, editDelete)
+		}
+		x, y = prevX, prevY
+	}
+	for ; x > 0; x-- {
+		ops = append(ops, editMatch)
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
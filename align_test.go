@@ -0,0 +1,64 @@
+package protocmp
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestAlign(t *testing.T) {
+	lcs := func(x, y []int) int {
+		t := make([][]int, len(x)+1)
+		for i := range t {
+			t[i] = make([]int, len(y)+1)
+		}
+		for i := len(x) - 1; i >= 0; i-- {
+			for j := len(y) - 1; j >= 0; j-- {
+				switch {
+				case x[i] == y[j]:
+					t[i][j] = t[i+1][j+1] + 1
+				case t[i+1][j] >= t[i][j+1]:
+					t[i][j] = t[i+1][j]
+				default:
+					t[i][j] = t[i][j+1]
+				}
+			}
+		}
+		return t[0][0]
+	}
+	random := func(r *rand.Rand) []int {
+		s := make([]int, r.Intn(12))
+		for i := range s {
+			s[i] = r.Intn(4)
+		}
+		return s
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 500; n++ {
+		x, y := random(r), random(r)
+		ops := align(len(x), len(y), func(i, j int) bool { return x[i] == y[j] })
+
+		i, j, matched := 0, 0, 0
+		for _, op := range ops {
+			switch op {
+			case editMatch:
+				if x[i] != y[j] {
+					t.Fatalf("%v %v: aligned %d with %d", x, y, x[i], y[j])
+				}
+				i++
+				j++
+				matched++
+			case editDelete:
+				i++
+			case editInsert:
+				j++
+			}
+		}
+		if i != len(x) || j != len(y) {
+			t.Fatalf("%v %v: script %v does not cover both sequences", x, y, ops)
+		}
+		if want := lcs(x, y); matched != want {
+			t.Fatalf("%v %v: matched %d elements, want %d", x, y, matched, want)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package protocmp
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const anyFullName protoreflect.FullName = "google.protobuf.Any"
+
+// equalAny compares two google.protobuf.Any messages by the messages embedded
+// in them. It reports false if the embedded messages cannot be unpacked, in
+// which case the Any messages should be compared as regular messages.
+func equalAny(opts *options, p fieldPath, mx, my protoreflect.Message) (matchErrs, bool) {
+	ux, ok := unpackAny(opts, mx)
+	if !ok {
+		return nil, false
+	}
+	uy, ok := unpackAny(opts, my)
+	if !ok || ux.Descriptor() != uy.Descriptor() {
+		return nil, false
+	}
+
+	step := anyStep(ux.Descriptor().FullName())
+	return equalMessage(opts, p.push(step.key()), ux, uy).Step(step), true
+}
+
+// unpackAny unmarshals the message embedded in a google.protobuf.Any message,
+// using the resolver to find the type referenced by its type URL.
+func unpackAny(opts *options, m protoreflect.Message) (protoreflect.Message, bool) {
+	fields := m.Descriptor().Fields()
+	typeURL := fields.ByName("type_url")
+	value := fields.ByName("value")
+	if typeURL == nil || value == nil || !m.IsValid() {
+		return nil, false
+	}
+
+	mt, err := opts.anyResolver().FindMessageByURL(m.Get(typeURL).String())
+	if err != nil {
+		return nil, false
+	}
+
+	um := mt.New()
+	if err := proto.Unmarshal(m.Get(value).Bytes(), um.Interface()); err != nil {
+		return nil, false
+	}
+
+	return um, true
+}
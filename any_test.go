@@ -0,0 +1,103 @@
+package protocmp
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/nbaztec/protocmp/protos/sample"
+)
+
+func TestAssertAnyUnpacked(t *testing.T) {
+	packed := func(m *sample.Outer_Inner) *any.Any {
+		v, err := ptypes.MarshalAny(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	tests := []struct {
+		name     string
+		expected *sample.Outer
+		input    *sample.Outer
+		opts     []Option
+		diff     *DiffError
+	}{
+		{
+			name: "equal",
+			expected: makeInput(func(v *sample.Outer) {
+				v.AnyType = packed(&sample.Outer_Inner{Id: "1"})
+			}),
+			input: makeInput(func(v *sample.Outer) {
+				v.AnyType = packed(&sample.Outer_Inner{Id: "1"})
+			}),
+		},
+		{
+			name: "different value",
+			expected: makeInput(func(v *sample.Outer) {
+				v.AnyType = packed(&sample.Outer_Inner{Id: "1"})
+			}),
+			input: makeInput(func(v *sample.Outer) {
+				v.AnyType = packed(&sample.Outer_Inner{Id: "2"})
+			}),
+			diff: &DiffError{
+				Field:    "any_type.(sample.Outer.Inner).id",
+				Message:  "value mismatch",
+				Expected: `"1"`,
+				Actual:   `"2"`,
+				Path:     Path{fieldStep(outerFields.ByName("any_type")), anyStep("sample.Outer.Inner"), fieldStep(innerFields.ByName("id"))},
+			},
+		},
+		{
+			name: "different serialization order",
+			expected: makeInput(func(v *sample.Outer) {
+				b := protowire.AppendTag(nil, 1, protowire.BytesType)
+				b = protowire.AppendString(b, "foo")
+				b = protowire.AppendTag(b, 2, protowire.VarintType)
+				b = protowire.AppendVarint(b, 1)
+				v.AnyType = &any.Any{TypeUrl: "type.googleapis.com/sample.Outer", Value: b}
+			}),
+			input: makeInput(func(v *sample.Outer) {
+				b := protowire.AppendTag(nil, 2, protowire.VarintType)
+				b = protowire.AppendVarint(b, 1)
+				b = protowire.AppendTag(b, 1, protowire.BytesType)
+				b = protowire.AppendString(b, "foo")
+				v.AnyType = &any.Any{TypeUrl: "type.googleapis.com/sample.Outer", Value: b}
+			}),
+		},
+		{
+			name: "unresolved with resolver",
+			expected: makeInput(func(v *sample.Outer) {
+				v.AnyType = packed(&sample.Outer_Inner{Id: "1"})
+			}),
+			input: makeInput(func(v *sample.Outer) {
+				v.AnyType = packed(&sample.Outer_Inner{Id: "2"})
+			}),
+			opts: []Option{AnyResolver(new(protoregistry.Types))},
+			diff: &DiffError{
+				Field:    "any_type.value",
+				Message:  "value mismatch (len 3 vs 3, first difference at offset 2)",
+				Expected: `00000000  0a 01 31                                          |..1|`,
+				Actual:   `00000000  0a 01 32                                          |..2|`,
+				Path:     Path{fieldStep(outerFields.ByName("any_type")), fieldStep(anyFields.ByName("value"))},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			check(
+				t,
+				tt.expected,
+				tt.input,
+				tt.diff,
+				tt.opts...,
+			)
+		})
+	}
+}
@@ -14,8 +14,8 @@ type TestingT interface {
 	Fail()
 }
 
-func AssertEqual(t TestingT, expected, actual proto.Message) {
-	err := Equal(expected, actual)
+func AssertEqual(t TestingT, expected, actual proto.Message, opts ...Option) {
+	err := Equal(expected, actual, opts...)
 	if err != nil {
 		frame := getFrame(1)
 		fmt.Printf("    %s: %s:%d\n        %s\n", t.Name(), path.Base(frame.File), frame.Line, strings.ReplaceAll(err.Error(), "\n", "\n            "))
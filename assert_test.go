@@ -33,7 +33,7 @@ func TestAssertEqualFails(t *testing.T) {
 	expectedOutput := `TestXYZ: assert_test.go:27
         Outer: value mismatch
             + <nil>
-            - <str_val:"foo" int_val:1 bool_val:true double_val:1.1 bytes_val:[1 2] repeated_type:[<id:"1"> <id:"2"> <nil>] map_type:map[A:<id:"AA"> B:<id:"BB"> C:<nil>] enum_type:NOT_OK oneof_string:"1" timestamp_type:<seconds:1598814300> duration_type:<seconds:1> any_type:<type_url:"mytype/v1" value:[5]> repeated_type_simple:[9 10 11] map_type_simple:map[A:20 B:30 C:40] nested_message:<inner:<id:"123">>>`
+            - <str_val:"foo" int_val:1 bool_val:true double_val:1.1 bytes_val:"\x01\x02" repeated_type:[<id:"1"> <id:"2"> <nil>] map_type:map[A:<id:"AA"> B:<id:"BB"> C:<nil>] enum_type:NOT_OK oneof_string:"1" timestamp_type:2020-08-30T19:05:00Z duration_type:1s any_type:<type_url:"mytype/v1" value:"\x05"> repeated_type_simple:[9 10 11] map_type_simple:map[A:20 B:30 C:40] nested_message:<inner:<id:"123">>>`
 
 	actualOutput := strings.TrimSpace(string(out))
 	if expectedOutput != actualOutput {
@@ -54,7 +54,7 @@ func TestAssertEqualFailsInverse(t *testing.T) {
 
 	expectedOutput := `TestXYZ: assert_test.go:49
         Outer: value mismatch
-            + <str_val:"foo" int_val:1 bool_val:true double_val:1.1 bytes_val:[1 2] repeated_type:[<id:"1"> <id:"2"> <nil>] map_type:map[A:<id:"AA"> B:<id:"BB"> C:<nil>] enum_type:NOT_OK oneof_string:"1" timestamp_type:<seconds:1598814300> duration_type:<seconds:1> any_type:<type_url:"mytype/v1" value:[5]> repeated_type_simple:[9 10 11] map_type_simple:map[A:20 B:30 C:40] nested_message:<inner:<id:"123">>>
+            + <str_val:"foo" int_val:1 bool_val:true double_val:1.1 bytes_val:"\x01\x02" repeated_type:[<id:"1"> <id:"2"> <nil>] map_type:map[A:<id:"AA"> B:<id:"BB"> C:<nil>] enum_type:NOT_OK oneof_string:"1" timestamp_type:2020-08-30T19:05:00Z duration_type:1s any_type:<type_url:"mytype/v1" value:"\x05"> repeated_type_simple:[9 10 11] map_type_simple:map[A:20 B:30 C:40] nested_message:<inner:<id:"123">>>
             - <nil>`
 
 	actualOutput := strings.TrimSpace(string(out))
@@ -0,0 +1,118 @@
+package protocmp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BytesFormat selects how mismatching bytes values are rendered in diffs.
+type BytesFormat int
+
+const (
+	// BytesHexDump renders a hex dump of the bytes around the first
+	// difference. This is the default.
+	BytesHexDump BytesFormat = iota
+	// BytesBase64 renders the bytes in standard base64 encoding.
+	BytesBase64
+	// BytesQuoted renders the bytes as a quoted Go string.
+	BytesQuoted
+)
+
+const (
+	// hexDumpWidth is the number of bytes per line of a hex dump.
+	hexDumpWidth = 16
+	// hexDumpContext is the number of lines of a hex dump shown before and
+	// after the line of the first difference.
+	hexDumpContext = 1
+)
+
+// firstDifference returns the offset of the first byte at which x and y
+// differ, which is the length of the shorter one if it is a prefix of the other.
+func firstDifference(x, y []byte) int {
+	for i := 0; i < len(x) && i < len(y); i++ {
+		if x[i] != y[i] {
+			return i
+		}
+	}
+	if len(x) < len(y) {
+		return len(x)
+	}
+	return len(y)
+}
+
+// bytesMismatch returns the error for two differing bytes values, rendered in
+// the configured format and naming their lengths and the offset of their first
+// difference.
+func bytesMismatch(opts *options, x, y []byte) *matchErr {
+	offset := firstDifference(x, y)
+	err := newMatchError(fmt.Sprintf("value mismatch (len %d vs %d, first difference at offset %d)", len(x), len(y), offset))
+	switch opts.bytesFormat {
+	case BytesBase64:
+		return err.Values(base64.StdEncoding.EncodeToString(x), base64.StdEncoding.EncodeToString(y))
+	case BytesQuoted:
+		return err.Values(strconv.Quote(string(x)), strconv.Quote(string(y)))
+	default:
+		start := (offset/hexDumpWidth - hexDumpContext) * hexDumpWidth
+		if start < 0 {
+			start = 0
+		}
+		end := (offset/hexDumpWidth + hexDumpContext + 1) * hexDumpWidth
+		return err.Values(hexDump(x, start, end), hexDump(y, start, end))
+	}
+}
+
+// equalBytes compares two bytes values.
+func equalBytes(opts *options, x, y []byte) matchErrs {
+	if bytes.Equal(x, y) {
+		return nil
+	}
+	return matchErrs{bytesMismatch(opts, x, y)}
+}
+
+// hexDump renders the bytes of b between the offsets start and end in the
+// format of hexdump -C, marking bytes left out before or after with "...".
+// Empty bytes are rendered as <empty>.
+func hexDump(b []byte, start, end int) string {
+	if len(b) == 0 {
+		return "<empty>"
+	}
+	if end > len(b) {
+		end = len(b)
+	}
+
+	var lines []string
+	if start > 0 {
+		lines = append(lines, "...")
+	}
+	for off := start; off < end; off += hexDumpWidth {
+		line := b[off:]
+		if len(line) > hexDumpWidth {
+			line = line[:hexDumpWidth]
+		}
+
+		var hex, ascii strings.Builder
+		for i := 0; i < hexDumpWidth; i++ {
+			if i < len(line) {
+				fmt.Fprintf(&hex, "%02x ", line[i])
+				if line[i] >= 0x20 && line[i] < 0x7f {
+					ascii.WriteByte(line[i])
+				} else {
+					ascii.WriteByte('.')
+				}
+			} else {
+				hex.WriteString("   ")
+			}
+			if i == hexDumpWidth/2-1 {
+				hex.WriteByte(' ')
+			}
+		}
+		lines = append(lines, fmt.Sprintf("%08x  %s |%s|", off, hex.String(), ascii.String()))
+	}
+	if end < len(b) {
+		lines = append(lines, "...")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,83 @@
+package protocmp
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nbaztec/protocmp/protos/sample"
+)
+
+func TestFormatBytes(t *testing.T) {
+	payload := []byte("The quick brown fox jumps over the lazy dog, again and again.")
+	changed := bytes.Replace(payload, []byte("lazy"), []byte("LAZY"), 1)
+
+	tests := []struct {
+		name string
+		opts []Option
+		diff *DiffError
+	}{
+		{
+			name: "hex dump window",
+			diff: &DiffError{
+				Field:   "bytes_val",
+				Message: "value mismatch (len 61 vs 61, first difference at offset 35)",
+				Expected: "...\n" +
+					"00000010  66 6f 78 20 6a 75 6d 70  73 20 6f 76 65 72 20 74  |fox jumps over t|\n" +
+					"00000020  68 65 20 6c 61 7a 79 20  64 6f 67 2c 20 61 67 61  |he lazy dog, aga|\n" +
+					"00000030  69 6e 20 61 6e 64 20 61  67 61 69 6e 2e           |in and again.|",
+				Actual: "...\n" +
+					"00000010  66 6f 78 20 6a 75 6d 70  73 20 6f 76 65 72 20 74  |fox jumps over t|\n" +
+					"00000020  68 65 20 4c 41 5a 59 20  64 6f 67 2c 20 61 67 61  |he LAZY dog, aga|\n" +
+					"00000030  69 6e 20 61 6e 64 20 61  67 61 69 6e 2e           |in and again.|",
+			},
+		},
+		{
+			name: "base64",
+			opts: []Option{FormatBytes(BytesBase64)},
+			diff: &DiffError{
+				Field:    "bytes_val",
+				Message:  "value mismatch (len 61 vs 61, first difference at offset 35)",
+				Expected: "VGhlIHF1aWNrIGJyb3duIGZveCBqdW1wcyBvdmVyIHRoZSBsYXp5IGRvZywgYWdhaW4gYW5kIGFnYWluLg==",
+				Actual:   "VGhlIHF1aWNrIGJyb3duIGZveCBqdW1wcyBvdmVyIHRoZSBMQVpZIGRvZywgYWdhaW4gYW5kIGFnYWluLg==",
+			},
+		},
+		{
+			name: "quoted",
+			opts: []Option{FormatBytes(BytesQuoted)},
+			diff: &DiffError{
+				Field:    "bytes_val",
+				Message:  "value mismatch (len 61 vs 61, first difference at offset 35)",
+				Expected: `"The quick brown fox jumps over the lazy dog, again and again."`,
+				Actual:   `"The quick brown fox jumps over the LAZY dog, again and again."`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			check(
+				t,
+				makeInput(func(v *sample.Outer) {
+					v.BytesVal = payload
+				}),
+				makeInput(func(v *sample.Outer) {
+					v.BytesVal = changed
+				}),
+				tt.diff,
+				tt.opts...,
+			)
+		})
+	}
+}
+
+func TestHexDump(t *testing.T) {
+	b := bytes.Repeat([]byte{0xab}, 80)
+	expected := "...\n" +
+		"00000010  ab ab ab ab ab ab ab ab  ab ab ab ab ab ab ab ab  |................|\n" +
+		"00000020  ab ab ab ab ab ab ab ab  ab ab ab ab ab ab ab ab  |................|\n" +
+		"..."
+	if got := hexDump(b, 16, 48); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
@@ -0,0 +1,38 @@
+package protocmp
+
+import (
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// isEmptyMessage reports whether the message is nil or has no populated
+// fields other than empty ones.
+func isEmptyMessage(m protoreflect.Message) bool {
+	if !m.IsValid() {
+		return true
+	}
+	if len(m.GetUnknown()) > 0 {
+		return false
+	}
+
+	empty := true
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		empty = isEmptyValue(fd, v)
+		return empty
+	})
+	return empty
+}
+
+// isEmptyValue reports whether the field value is an empty list, an empty map
+// or an empty message.
+func isEmptyValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+	switch {
+	case fd.IsList():
+		return v.List().Len() == 0
+	case fd.IsMap():
+		return v.Map().Len() == 0
+	case fd.Message() != nil:
+		return isEmptyMessage(v.Message())
+	default:
+		return false
+	}
+}
@@ -1,268 +1,537 @@
 package protocmp
 
 import (
-	"bytes"
 	"fmt"
-	"math"
 	"reflect"
 
 	"github.com/golang/protobuf/proto"
-	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
-func Equal(x, y proto.Message) *DiffError {
-	if err := equal(proto.MessageV2(x), proto.MessageV2(y)); err != nil {
-		return err.Diff()
+// Equal compares two messages and returns the first difference found, if any.
+// Differences are found in the order documented on EqualAll.
+func Equal(x, y proto.Message, opts ...Option) *DiffError {
+	if errs := equal(newOptions(opts), proto.MessageV2(x), proto.MessageV2(y)); len(errs) > 0 {
+		return errs[0].Diff()
 	}
 
 	return nil
 }
 
-func equal(x, y protoreflect.ProtoMessage) *matchErr {
+// EqualAll compares two messages and returns every difference found, if any.
+//
+// Differences are reported in a stable order: the fields of a message in
+// field number order, followed by the fields only set in the actual message in
+// field number order and by its unknown fields by field number. Elements of
+// repeated fields are visited front to back and entries of maps in key order.
+func EqualAll(x, y proto.Message, opts ...Option) DiffErrors {
+	if errs := equal(newOptions(opts), proto.MessageV2(x), proto.MessageV2(y)); len(errs) > 0 {
+		return errs.Diff()
+	}
+
+	return nil
+}
+
+func equal(opts *options, x, y protoreflect.ProtoMessage) matchErrs {
 	vx := reflect.ValueOf(x)
 	vy := reflect.ValueOf(y)
 
 	xNil := !vx.IsValid() || vx.IsNil()
 	yNil := !vy.IsValid() || vy.IsNil()
 	if xNil || yNil {
-		if xNil && yNil {
+		if xNil && (yNil || opts.partial) {
+			return nil
+		}
+
+		if opts.equateEmpty && (xNil || isEmptyMessage(x.ProtoReflect())) && (yNil || isEmptyMessage(y.ProtoReflect())) {
 			return nil
 		}
 
 		if yNil {
-			return newMatchError("value mismatch").Values(fmtMessage(x.ProtoReflect()), y).Field(x.ProtoReflect().Descriptor().Name())
+			return matchErrs{newMatchError("value mismatch").Values(fmtMessage(x.ProtoReflect()), y).Field(x.ProtoReflect().Descriptor().Name())}
 		}
 
-		return newMatchError("value mismatch").Values(x, fmtMessage(y.ProtoReflect())).Field(y.ProtoReflect().Descriptor().Name())
+		return matchErrs{newMatchError("value mismatch").Values(x, fmtMessage(y.ProtoReflect())).Field(y.ProtoReflect().Descriptor().Name())}
 
 	}
 
 	mx := x.ProtoReflect()
 	my := y.ProtoReflect()
-	if mx.IsValid() != my.IsValid() {
+	if mx.IsValid() != my.IsValid() && !(opts.equateEmpty && isEmptyMessage(mx) && isEmptyMessage(my)) {
 		if mx.IsValid() {
-			return newMatchError("value mismatch").Values(fmtMessage(mx), nil).Field(mx.Descriptor().Name())
+			return matchErrs{newMatchError("value mismatch").Values(fmtMessage(mx), nil).Field(mx.Descriptor().Name())}
 		}
 
-		return newMatchError("value mismatch").Values(nil, fmtMessage(my)).Field(my.Descriptor().Name())
-	}
-	if err := equalMessage(mx, my); err != nil {
-		return err
+		return matchErrs{newMatchError("value mismatch").Values(nil, fmtMessage(my)).Field(my.Descriptor().Name())}
 	}
 
-	return nil
+	return equalMessage(opts, nil, mx, my)
 }
 
 func fmtError(v protoreflect.Value, fd protoreflect.FieldDescriptor) *matchErr {
 	switch {
 	case fd.IsList():
-		return newMatchError("value mismatch").Field(fd.Name()).Values(fmtList(v.List(), fd), nil)
+		return newMatchError("value mismatch").Step(fieldStep(fd)).Values(fmtList(v.List(), fd), nil)
 	case fd.IsMap():
-		return newMatchError("value mismatch").Field(fd.Name()).Values(fmtMap(v.Map(), fd), nil)
+		return newMatchError("value mismatch").Step(fieldStep(fd)).Values(fmtMap(v.Map(), fd), nil)
 	default:
 		switch fd.Kind() {
 		case protoreflect.MessageKind, protoreflect.GroupKind:
-			return newMatchError("value mismatch").Field(fd.Name()).Values(fmtMessage(v.Message()), nil)
+			return newMatchError("value mismatch").Step(fieldStep(fd)).Values(fmtMessage(v.Message()), nil)
 		case protoreflect.StringKind:
-			return newMatchError("value mismatch").Field(fd.Name()).ValueExpected(quoteString(v.Interface()))
+			return newMatchError("value mismatch").Step(fieldStep(fd)).ValueExpected(text(v.String()))
 		}
 
-		return newMatchError("value mismatch").Field(fd.Name()).Values(v.Interface(), nil)
+		return newMatchError("value mismatch").Step(fieldStep(fd)).Values(v.Interface(), nil)
 	}
 }
 
-func fmtMissingFieldError(fd protoreflect.FieldDescriptor, vx, vy protoreflect.Value) *matchErr {
+func fmtMissingFieldError(opts *options, fd protoreflect.FieldDescriptor, vx, vy protoreflect.Value) *matchErr {
 	switch  {
 	case fd.IsList() || fd.IsMap():
-		fallthrough
+		return fmtError(vx, fd).ValueActual(nil)
+	case fd.Message() != nil && isWrapperMessage(fd.Message()):
+		return fmtError(vx, fd).ValueActual("null")
 	case fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind:
 		return fmtError(vx, fd).ValueActual(nil)
 	case fd.Kind() == protoreflect.StringKind:
-		return fmtError(vx, fd).ValueActual(quoteString(""))
+		return fmtError(vx, fd).ValueActual(text(""))
+	case fd.Kind() == protoreflect.BytesKind:
+		return bytesMismatch(opts, vx.Bytes(), vy.Bytes()).Step(fieldStep(fd))
 	default:
 		return fmtError(vx, fd).ValueActual(vy.Interface())
 	}
 }
 
+// fmtUnexpectedFieldError returns the error for a field set only in the actual
+// message, the mirror image of fmtMissingFieldError. Bytes are compared in
+// order, as their error names the lengths of both sides.
+func fmtUnexpectedFieldError(opts *options, fd protoreflect.FieldDescriptor, vy protoreflect.Value) *matchErr {
+	if fd.Kind() == protoreflect.BytesKind && !fd.IsList() {
+		return bytesMismatch(opts, fd.Default().Bytes(), vy.Bytes()).Step(fieldStep(fd))
+	}
+	return fmtMissingFieldError(opts, fd, vy, fd.Default()).ValuesSwap()
+}
+
 // equalMessage compares two messages.
-func equalMessage(mx, my protoreflect.Message) *matchErr {
-	if mx.Descriptor() != my.Descriptor() {
-		return newMatchError("descriptors don't match")
+func equalMessage(opts *options, p fieldPath, mx, my protoreflect.Message) matchErrs {
+	if mx.Descriptor().FullName() != my.Descriptor().FullName() {
+		return matchErrs{newMatchError("descriptors don't match").Values(mx.Descriptor().FullName(), my.Descriptor().FullName())}
+	}
+
+	if mx.IsValid() != my.IsValid() && opts.equateEmpty && isEmptyMessage(mx) && isEmptyMessage(my) {
+		return nil
+	}
+
+	if !mx.IsValid() && opts.partial {
+		return nil
 	}
 
 	if mx.IsValid() && !my.IsValid() {
-		return newMatchError("value mismatch").Values(fmtMessage(mx), nil)
+		return matchErrs{newMatchError("value mismatch").Values(fmtMessage(mx), nil)}
 	}
 
 	if !mx.IsValid() && my.IsValid() {
-		return newMatchError("value mismatch").Values(nil, fmtMessage(my))
+		return matchErrs{newMatchError("value mismatch").Values(nil, fmtMessage(my))}
 	}
 
+	if compare, ok := opts.comparers[mx.Descriptor().FullName()]; ok && mx.IsValid() {
+		if !compare(mx, my) {
+			return matchErrs{newMatchError("value mismatch").Values(fmtMessage(mx), fmtMessage(my))}
+		}
+		return nil
+	}
 
-	nx := 0
-	var equalErr *matchErr
-	mx.Range(func(fd protoreflect.FieldDescriptor, vx protoreflect.Value) bool {
-		nx++
-		vy := my.Get(fd)
+	if isTimeMessage(mx.Descriptor()) {
+		return equalTime(opts, p, mx, my)
+	}
+
+	if isStructMessage(mx.Descriptor()) {
+		return equalStruct(opts, p, mx, my)
+	}
 
-		if !my.Has(fd) {
-			equalErr = fmtMissingFieldError(fd, vx, vy)
-			return false
+	if mx.Descriptor().FullName() == anyFullName {
+		if errs, ok := equalAny(opts, p, mx, my); ok {
+			return errs
+		}
+	}
+
+	var errs matchErrs
+	mismatched := equalOneofs(opts, p, mx, my)
+	sortedFieldRange(mx, func(fd protoreflect.FieldDescriptor, vx protoreflect.Value) bool {
+		fp := p.push(string(fd.Name()))
+		if opts.ignored(fd, fp) {
+			return true
+		}
+		if od := fd.ContainingOneof(); od != nil && mismatched[od.Name()] != nil {
+			errs = append(errs, mismatched[od.Name()])
+			return true
 		}
 
-		if err := equalField(fd, vx, vy); err != nil {
-			equalErr = err
-			return false
+		fdy := fieldOf(my.Descriptor(), fd)
+		if fdy == nil || !my.Has(fdy) {
+			if transformers := fieldTransformers(opts, fp, fd); len(transformers) > 0 {
+				errs = append(errs, equalTransformed(opts, fp, fd, transformers, vx, fd.Default()).Step(fieldStep(fd))...)
+			} else if toleratedFloat(opts, fp, fd) {
+				errs = append(errs, equalScalar(opts, fp, fd, vx, fd.Default()).Step(fieldStep(fd))...)
+			} else if !opts.equateMissing(fd, vx) {
+				errs = append(errs, fmtMissingFieldError(opts, fd, vx, fd.Default()))
+			}
+			return true
 		}
 
+		errs = append(errs, equalField(opts, fp, fd, vx, my.Get(fdy))...)
 		return true
 	})
 
-	if equalErr != nil {
-		return equalErr
+	if opts.partial {
+		return append(errs, equalUnknown(opts, mx.GetUnknown(), my.GetUnknown())...)
 	}
-	ny := 0
-	my.Range(func(fd protoreflect.FieldDescriptor, vy protoreflect.Value) bool {
-		ny++
-		vx := mx.Get(fd)
 
-		if !mx.Has(fd) {
-			equalErr = fmtMissingFieldError(fd, vy, vx).ValuesSwap()
-			return false
+	sortedFieldRange(my, func(fd protoreflect.FieldDescriptor, vy protoreflect.Value) bool {
+		fp := p.push(string(fd.Name()))
+		if opts.ignored(fd, fp) {
+			return true
+		}
+		if od := fd.ContainingOneof(); od != nil && mismatched[od.Name()] != nil {
+			return true
+		}
+
+		fdx := fieldOf(mx.Descriptor(), fd)
+		if fdx != nil && mx.Has(fdx) {
+			return true
+		}
+		if transformers := fieldTransformers(opts, fp, fd); len(transformers) > 0 {
+			errs = append(errs, equalTransformed(opts, fp, fd, transformers, fd.Default(), vy).Step(fieldStep(fd))...)
+		} else if toleratedFloat(opts, fp, fd) {
+			errs = append(errs, equalScalar(opts, fp, fd, fd.Default(), vy).Step(fieldStep(fd))...)
+		} else if !opts.equateMissing(fd, vy) {
+			errs = append(errs, fmtUnexpectedFieldError(opts, fd, vy))
 		}
 		return true
 	})
 
-	if equalErr != nil {
-		return equalErr
+	return append(errs, equalUnknown(opts, mx.GetUnknown(), my.GetUnknown())...)
+}
+
+// fieldOf returns the field of md that corresponds to fd. The field descriptors
+// of distinct descriptor instances of the same message type correspond when
+// they share the same number, name, kind and cardinality. Returns nil if md
+// has no such field.
+func fieldOf(md protoreflect.MessageDescriptor, fd protoreflect.FieldDescriptor) protoreflect.FieldDescriptor {
+	if fd.IsExtension() || fd.ContainingMessage() == md {
+		return fd
 	}
 
-	return equalUnknown(mx.GetUnknown(), my.GetUnknown())
+	f := md.Fields().ByNumber(fd.Number())
+	if f == nil || f.Name() != fd.Name() || f.Kind() != fd.Kind() || f.Cardinality() != fd.Cardinality() || f.IsMap() != fd.IsMap() {
+		return nil
+	}
+	return f
 }
 
 // equalField compares two fields.
-func equalField(fd protoreflect.FieldDescriptor, x, y protoreflect.Value) *matchErr {
+func equalField(opts *options, p fieldPath, fd protoreflect.FieldDescriptor, x, y protoreflect.Value) matchErrs {
 	switch {
 	case fd.IsList():
-		if err := equalList(fd, x.List(), y.List()); err != nil {
-			return err.Field(fd.Name())
-		}
+		return equalList(opts, p, fd, x.List(), y.List()).Step(fieldStep(fd))
 	case fd.IsMap():
-		if err := equalMap(fd, x.Map(), y.Map()); err != nil {
-			return err.Field(fd.Name())
-		}
+		return equalMap(opts, p, fd, x.Map(), y.Map()).Step(fieldStep(fd))
 	default:
-		if err := equalValue(fd, x, y); err != nil {
-			return err.Field(fd.Name())
-		}
+		return equalValue(opts, p, fd, x, y).Step(fieldStep(fd))
 	}
-
-	return nil
 }
 
-// equalMap compares two maps.
-func equalMap(fd protoreflect.FieldDescriptor, x, y protoreflect.Map) *matchErr {
-	if x.Len() != y.Len() {
-		return newMatchError("length mismatch").Values(x.Len(), y.Len())
-	}
-	var equalErr *matchErr
-	x.Range(func(k protoreflect.MapKey, vx protoreflect.Value) bool {
-		vy := y.Get(k)
-		if !y.Has(k) {
-			equalErr = newMatchError("missing key").Field(protoreflect.Name(fmt.Sprintf("[%s]", k.String())))
-			return false
-		}
-		if err := equalValue(fd.MapValue(), vx, vy); err != nil {
-			equalErr = err.Field(protoreflect.Name(fmt.Sprintf("[%s]", k.String())))
-			return false
+// equalMap compares two maps entry by entry in key order, reporting keys only
+// present in the expected map as missing and keys only present in the actual
+// map as unexpected.
+func equalMap(opts *options, p fieldPath, fd protoreflect.FieldDescriptor, x, y protoreflect.Map) matchErrs {
+	keys := make([]protoreflect.MapKey, 0, x.Len()+y.Len())
+	x.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+		keys = append(keys, k)
+		return true
+	})
+	y.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+		if !x.Has(k) {
+			keys = append(keys, k)
 		}
 		return true
 	})
-	return equalErr
+	sortMapKeys(keys, fd.MapKey().Kind())
+
+	var errs matchErrs
+	for _, k := range keys {
+		step := mapKeyStep(k)
+		kp := p.push(step.key())
+		if opts.ignoredPath(kp) {
+			continue
+		}
+
+		switch {
+		case !y.Has(k):
+			errs = append(errs, newMatchError("missing key").Step(step).Values(fmtValue(x.Get(k), fd.MapValue()), nil))
+		case !x.Has(k):
+			if !opts.partial {
+				errs = append(errs, newMatchError("unexpected key").Step(step).Values(nil, fmtValue(y.Get(k), fd.MapValue())))
+			}
+		default:
+			errs = append(errs, equalValue(opts, kp, fd.MapValue(), x.Get(k), y.Get(k)).Step(step)...)
+		}
+	}
+	return errs
 }
 
 // equalList compares two lists.
-func equalList(fd protoreflect.FieldDescriptor, x, y protoreflect.List) *matchErr {
+func equalList(opts *options, p fieldPath, fd protoreflect.FieldDescriptor, x, y protoreflect.List) matchErrs {
+	kd, err := opts.listKey(p, fd)
+	if err != nil {
+		return matchErrs{newMatchError(err.Error()).Values(fmtList(x, fd), fmtList(y, fd))}
+	}
+	if kd != nil {
+		return equalListKeyed(opts, p, fd, kd, x, y)
+	}
+	if opts.unorderedList(p) {
+		return equalListUnordered(opts, p, fd, x, y)
+	}
+	if opts.sortedList(p) {
+		x, y = sortList(fd, x), sortList(fd, y)
+	}
 	if x.Len() != y.Len() {
-		return newMatchError("length mismatch").Values(x.Len(), y.Len())
+		return equalListSequence(opts, p, fd, x, y)
 	}
-	for i := x.Len() - 1; i >= 0; i-- {
-		if err := equalValue(fd, x.Get(i), y.Get(i)); err != nil {
-			return err.Field(protoreflect.Name(fmt.Sprintf("[%d]", i)))
+	var errs matchErrs
+	for i := 0; i < x.Len(); i++ {
+		ip := p.push(fmt.Sprintf("[%d]", i))
+		if opts.ignoredPath(ip) {
+			continue
 		}
+		errs = append(errs, equalValue(opts, ip, fd, x.Get(i), y.Get(i)).Step(indexStep(i))...)
 	}
-	return nil
+	return errs
 }
 
-// equalValue compares two singular values.
-func equalValue(fd protoreflect.FieldDescriptor, x, y protoreflect.Value) *matchErr {
-	switch {
-	case fd.Message() != nil:
-		if err := equalMessage(x.Message(), y.Message()); err != nil {
-			return err
+// equalListSequence compares two lists of different lengths by aligning them
+// on their longest common subsequence. Elements of x left out of the alignment
+// are reported as missing at their index in x and elements of y as unexpected
+// at their index in y, except that between two aligned elements they are
+// paired up and compared as modifications at their index in x. Message
+// elements are aligned when they are identical, and only aligned or paired
+// elements are compared with the options.
+func equalListSequence(opts *options, p fieldPath, fd protoreflect.FieldDescriptor, x, y protoreflect.List) matchErrs {
+	ops := align(x.Len(), y.Len(), func(i, j int) bool {
+		return sameElement(opts, p.push(fmt.Sprintf("[%d]", i)), fd, x.Get(i), y.Get(j))
+	})
+
+	var errs matchErrs
+	var missing, unexpected []int
+	flush := func() {
+		for k, i := range missing {
+			ip := p.push(fmt.Sprintf("[%d]", i))
+			if opts.ignoredPath(ip) {
+				continue
+			}
+			if k < len(unexpected) {
+				errs = append(errs, equalValue(opts, ip, fd, x.Get(i), y.Get(unexpected[k])).Step(indexStep(i))...)
+				continue
+			}
+			errs = append(errs, newMatchError("missing element").Values(fmtValue(x.Get(i), fd), nil).Step(indexStep(i)))
 		}
-		return nil
-	case fd.Kind() == protoreflect.BytesKind:
-		if !bytes.Equal(x.Bytes(), y.Bytes()) {
-			return newMatchError("value mismatch").Values(x.Bytes(), y.Bytes())
+		for k := len(missing); k < len(unexpected) && !opts.partial; k++ {
+			j := unexpected[k]
+			if opts.ignoredPath(p.push(fmt.Sprintf("[%d]", j))) {
+				continue
+			}
+			errs = append(errs, newMatchError("unexpected element").Values(nil, fmtValue(y.Get(j), fd)).Step(indexStep(j)))
 		}
-	case fd.Kind() == protoreflect.FloatKind, fd.Kind() == protoreflect.DoubleKind:
-		fx := x.Float()
-		fy := y.Float()
-		if math.IsNaN(fx) || math.IsNaN(fy) {
-			if !math.IsNaN(fx) && math.IsNaN(fy) {
-				return newMatchError("value mismatch").Values(fx, fy)
+		missing, unexpected = nil, nil
+	}
+
+	i, j := 0, 0
+	for _, op := range ops {
+		switch op {
+		case editMatch:
+			flush()
+			if ip := p.push(fmt.Sprintf("[%d]", i)); !opts.ignoredPath(ip) {
+				errs = append(errs, equalValue(opts, ip, fd, x.Get(i), y.Get(j)).Step(indexStep(i))...)
 			}
-			if math.IsNaN(fx) && !math.IsNaN(fy) {
-				return newMatchError("value mismatch").Values(fx, fy)
+			i++
+			j++
+		case editDelete:
+			missing = append(missing, i)
+			i++
+		case editInsert:
+			unexpected = append(unexpected, j)
+			j++
+		}
+	}
+	flush()
+
+	return errs
+}
+
+// sameElement is the cheap equality used to align lists. Messages are aligned
+// only if they are identical, whereas scalars are compared with the options.
+// With Partial, messages are compared with the options too, so that a template
+// aligns with the elements it matches.
+func sameElement(opts *options, p fieldPath, fd protoreflect.FieldDescriptor, x, y protoreflect.Value) bool {
+	if fd.Message() != nil && !opts.partial {
+		return proto.Equal(proto.MessageV1(x.Message().Interface()), proto.MessageV1(y.Message().Interface()))
+	}
+	return len(equalValue(opts, p, fd, x, y)) == 0
+}
+
+// equalListUnordered compares two lists as multisets, pairing each element
+// with an equal element at any position of the other list. Each element is
+// paired with a free equal element if there is one, and otherwise by taking
+// over the element of another pair that can be paired elsewhere, so that under
+// tolerances an element is not left unpaired because its only match was taken.
+func equalListUnordered(opts *options, p fieldPath, fd protoreflect.FieldDescriptor, x, y protoreflect.List) matchErrs {
+	// eq caches the comparisons of x[i] and y[j]: 1 if equal, -1 if not.
+	eq := make([][]int8, x.Len())
+	equal := func(i, j int) bool {
+		if eq[i] == nil {
+			eq[i] = make([]int8, y.Len())
+		}
+		if eq[i][j] == 0 {
+			eq[i][j] = -1
+			if len(equalValue(opts, p.push(fmt.Sprintf("[%d]", i)), fd, x.Get(i), y.Get(j))) == 0 {
+				eq[i][j] = 1
 			}
+		}
+		return eq[i][j] > 0
+	}
 
-			return nil
+	ignored := make([]bool, y.Len())
+	owner := make([]int, y.Len())
+	for j := range owner {
+		ignored[j] = opts.ignoredPath(p.push(fmt.Sprintf("[%d]", j)))
+		owner[j] = -1
+	}
+	var visited []bool
+	var augment func(i int) bool
+	augment = func(i int) bool {
+		for j := range owner {
+			if ignored[j] || visited[j] || !equal(i, j) {
+				continue
+			}
+			visited[j] = true
+			if owner[j] < 0 || augment(owner[j]) {
+				owner[j] = i
+				return true
+			}
 		}
-		if fx != fy {
-			return newMatchError("value mismatch").Values(fx, fy)
+		return false
+	}
+	pair := func(i int) bool {
+		for j := range owner {
+			if !ignored[j] && owner[j] < 0 && equal(i, j) {
+				owner[j] = i
+				return true
+			}
 		}
+		visited = make([]bool, y.Len())
+		return augment(i)
+	}
 
-		return nil
-	case fd.Kind() == protoreflect.StringKind:
-		if x.Interface() != y.Interface() {
-			return newMatchError("value mismatch").Values(quoteString(x.Interface()), quoteString(y.Interface()))
+	var errs matchErrs
+	for i := 0; i < x.Len(); i++ {
+		if opts.ignoredPath(p.push(fmt.Sprintf("[%d]", i))) {
+			continue
 		}
+		if !pair(i) {
+			errs = append(errs, newMatchError("missing element").Values(fmtValue(x.Get(i), fd), nil).Step(indexStep(i)))
+		}
+	}
+	if opts.partial {
+		return errs
+	}
+	for j := 0; j < y.Len(); j++ {
+		if ignored[j] || owner[j] >= 0 {
+			continue
+		}
+		errs = append(errs, newMatchError("unexpected element").Values(nil, fmtValue(y.Get(j), fd)).Step(indexStep(j)))
+	}
+	return errs
+}
 
-	default:
-		if x.Interface() != y.Interface() {
-			return newMatchError("value mismatch").Values(x.Interface(), y.Interface())
+// equalListKeyed compares two lists of messages by pairing the elements that
+// share the same value for the key field kd, regardless of their position.
+func equalListKeyed(opts *options, p fieldPath, fd, kd protoreflect.FieldDescriptor, x, y protoreflect.List) matchErrs {
+	keyOf := func(v protoreflect.Value) PathStep {
+		if !v.Message().IsValid() {
+			return listKeyStep(kd, protoreflect.Value{})
 		}
+		return listKeyStep(kd, v.Message().Get(kd))
 	}
 
-	return nil
-}
+	ys := make(map[string][]int)
+	for j := 0; j < y.Len(); j++ {
+		k := keyOf(y.Get(j)).key()
+		ys[k] = append(ys[k], j)
+	}
 
-// equalUnknown compares unknown fields by direct comparison on the raw bytes
-// of each individual field number.
-func equalUnknown(x, y protoreflect.RawFields) *matchErr {
-	if len(x) != len(y) {
-		return newMatchError("length mismatch").Values(len(x), len(y))
+	matched := make([]bool, y.Len())
+	var errs matchErrs
+	for i := 0; i < x.Len(); i++ {
+		step := keyOf(x.Get(i))
+		k := step.key()
+		kp := p.push(k)
+		if opts.ignoredPath(kp) {
+			continue
+		}
+		if len(ys[k]) == 0 {
+			errs = append(errs, newMatchError("missing element").Values(fmtValue(x.Get(i), fd), nil).Step(step))
+			continue
+		}
+		j := ys[k][0]
+		ys[k] = ys[k][1:]
+		matched[j] = true
+		errs = append(errs, equalValue(opts, kp, fd, x.Get(i), y.Get(j)).Step(step)...)
+	}
+	if opts.partial {
+		return errs
 	}
-	if !bytes.Equal(x, y) {
-		return newMatchError("value mismatch").Values(x, y)
+	for j := 0; j < y.Len(); j++ {
+		step := keyOf(y.Get(j))
+		if matched[j] || opts.ignoredPath(p.push(step.key())) {
+			continue
+		}
+		errs = append(errs, newMatchError("unexpected element").Values(nil, fmtValue(y.Get(j), fd)).Step(step))
 	}
+	return errs
+}
 
-	mx := make(map[protoreflect.FieldNumber]protoreflect.RawFields)
-	my := make(map[protoreflect.FieldNumber]protoreflect.RawFields)
-	for len(x) > 0 {
-		fnum, _, n := protowire.ConsumeField(x)
-		mx[fnum] = append(mx[fnum], x[:n]...)
-		x = x[n:]
+// equalValue compares two singular values.
+func equalValue(opts *options, p fieldPath, fd protoreflect.FieldDescriptor, x, y protoreflect.Value) matchErrs {
+	if fd.Message() != nil && isWrapperMessage(fd.Message()) && opts.comparers[fd.Message().FullName()] == nil {
+		return equalWrapper(opts, p, x.Message(), y.Message())
+	}
+	if fd.Message() != nil {
+		return equalMessage(opts, p, x.Message(), y.Message())
 	}
-	for len(y) > 0 {
-		fnum, _, n := protowire.ConsumeField(y)
-		my[fnum] = append(my[fnum], y[:n]...)
-		y = y[n:]
+	if transformers := opts.transformers(p, fd); len(transformers) > 0 {
+		return equalTransformed(opts, p, fd, transformers, x, y)
 	}
-	if !reflect.DeepEqual(mx, my) {
-		return newMatchError("value mismatch").Values(mx, my)
+
+	return equalScalar(opts, p, fd, x, y)
+}
+
+// equalScalar compares two singular values of a scalar kind.
+func equalScalar(opts *options, p fieldPath, fd protoreflect.FieldDescriptor, x, y protoreflect.Value) matchErrs {
+	switch {
+	case fd.Kind() == protoreflect.BytesKind:
+		return equalBytes(opts, x.Bytes(), y.Bytes())
+	case fd.Kind() == protoreflect.FloatKind, fd.Kind() == protoreflect.DoubleKind:
+		return equalFloat(opts, p, fd, x.Float(), y.Float())
+	case fd.Kind() == protoreflect.StringKind:
+		if x.Interface() != y.Interface() {
+			return matchErrs{newMatchError("value mismatch").Values(text(x.String()), text(y.String()))}
+		}
+
+	default:
+		if x.Interface() != y.Interface() {
+			return matchErrs{newMatchError("value mismatch").Values(x.Interface(), y.Interface())}
+		}
 	}
 
 	return nil
@@ -2,11 +2,19 @@ package protocmp
 
 import (
 	"reflect"
+	"strconv"
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/any"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	"github.com/nbaztec/protocmp/protos/sample"
 )
@@ -39,7 +47,7 @@ func TestAssertNil(t *testing.T) {
 		&DiffError{
 			Field:    "Outer",
 			Message:  "value mismatch",
-			Expected: `<str_val:"foo" int_val:1 bool_val:true double_val:1.1 bytes_val:[1 2] repeated_type:[<id:"1"> <id:"2"> <nil>] map_type:map[A:<id:"AA"> B:<id:"BB"> C:<nil>] enum_type:NOT_OK oneof_string:"1" timestamp_type:<seconds:1598814300> duration_type:<seconds:1> any_type:<type_url:"mytype/v1" value:[5]> repeated_type_simple:[9 10 11] map_type_simple:map[A:20 B:30 C:40] nested_message:<inner:<id:"123">>>`,
+			Expected: `<str_val:"foo" int_val:1 bool_val:true double_val:1.1 bytes_val:"\x01\x02" repeated_type:[<id:"1"> <id:"2"> <nil>] map_type:map[A:<id:"AA"> B:<id:"BB"> C:<nil>] enum_type:NOT_OK oneof_string:"1" timestamp_type:2020-08-30T19:05:00Z duration_type:1s any_type:<type_url:"mytype/v1" value:"\x05"> repeated_type_simple:[9 10 11] map_type_simple:map[A:20 B:30 C:40] nested_message:<inner:<id:"123">>>`,
 			Actual:   `<nil>`,
 		},
 	)
@@ -111,55 +119,105 @@ func TestAssertDouble(t *testing.T) {
 
 func TestAssertBytes(t *testing.T) {
 	tests := []struct {
-		name         string
-		input        *sample.Outer
-		diffExpected string
-		diffActual   string
+		name           string
+		input          *sample.Outer
+		diffMessage    string
+		inverseMessage string
+		diffExpected   string
+		diffActual     string
 	}{
 		{
 			name: "nil value",
 			input: makeInput(func(v *sample.Outer) {
 				v.BytesVal = nil
 			}),
-			diffExpected: `[1 2]`,
-			diffActual:   `[]`,
+			diffMessage:    "value mismatch (len 2 vs 0, first difference at offset 0)",
+			inverseMessage: "value mismatch (len 0 vs 2, first difference at offset 0)",
+			diffExpected:   `00000000  01 02                                             |..|`,
+			diffActual:     `<empty>`,
 		},
 		{
 			name: "different length",
 			input: makeInput(func(v *sample.Outer) {
 				v.BytesVal = []byte{0x6}
 			}),
-			diffExpected: `[1 2]`,
-			diffActual:   `[6]`,
+			diffMessage:    "value mismatch (len 2 vs 1, first difference at offset 0)",
+			inverseMessage: "value mismatch (len 1 vs 2, first difference at offset 0)",
+			diffExpected:   `00000000  01 02                                             |..|`,
+			diffActual:     `00000000  06                                                |.|`,
+		},
+		{
+			name: "prefix",
+			input: makeInput(func(v *sample.Outer) {
+				v.BytesVal = []byte{0x1}
+			}),
+			diffMessage:    "value mismatch (len 2 vs 1, first difference at offset 1)",
+			inverseMessage: "value mismatch (len 1 vs 2, first difference at offset 1)",
+			diffExpected:   `00000000  01 02                                             |..|`,
+			diffActual:     `00000000  01                                                |.|`,
 		},
 		{
 			name: "different value",
 			input: makeInput(func(v *sample.Outer) {
 				v.BytesVal = []byte{0x6, 0x8}
 			}),
-			diffExpected: `[1 2]`,
-			diffActual:   `[6 8]`,
+			diffMessage:    "value mismatch (len 2 vs 2, first difference at offset 0)",
+			inverseMessage: "value mismatch (len 2 vs 2, first difference at offset 0)",
+			diffExpected:   `00000000  01 02                                             |..|`,
+			diffActual:     `00000000  06 08                                             |..|`,
 		},
 	}
 
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			check(
-				t,
-				makeInput(nil),
-				tt.input,
-				&DiffError{
-					Field:    "bytes_val",
-					Message:  "value mismatch",
-					Expected: tt.diffExpected,
-					Actual:   tt.diffActual,
-				},
-			)
+			want := &DiffError{
+				Field:    "bytes_val",
+				Message:  tt.diffMessage,
+				Expected: tt.diffExpected,
+				Actual:   tt.diffActual,
+			}
+			if actual := withoutPath(want, Equal(makeInput(nil), tt.input)); !reflect.DeepEqual(want, actual) {
+				t.Errorf("mismatch err\n++ want:\n%s\n-- got:\n%s", want, actual)
+			}
+
+			inverse := &DiffError{
+				Field:    "bytes_val",
+				Message:  tt.inverseMessage,
+				Expected: tt.diffActual,
+				Actual:   tt.diffExpected,
+			}
+			if actual := withoutPath(inverse, Equal(tt.input, makeInput(nil))); !reflect.DeepEqual(inverse, actual) {
+				t.Errorf("(inverse) mismatch err\n++ want:\n%s\n-- got:\n%s", inverse, actual)
+			}
 		})
 	}
 }
 
+func TestAssertListSequenceLarge(t *testing.T) {
+	list := func(n int) []*sample.Outer_Inner {
+		l := make([]*sample.Outer_Inner, n)
+		for i := range l {
+			l[i] = &sample.Outer_Inner{Id: strconv.Itoa(i)}
+		}
+		return l
+	}
+	expected := makeInput(func(v *sample.Outer) {
+		v.RepeatedType = list(5000)
+	})
+	input := makeInput(func(v *sample.Outer) {
+		v.RepeatedType = list(5001)
+	})
+
+	diffs := EqualAll(expected, input)
+	want := DiffErrors{
+		{Field: "repeated_type.[5000]", Message: "unexpected element", Expected: `<nil>`, Actual: `<id:"5000">`, Path: Path{fieldStep(outerFields.ByName("repeated_type")), indexStep(5000)}},
+	}
+	if !reflect.DeepEqual(want, diffs) {
+		t.Errorf("mismatch:\nexpected: %v\nactual:   %v", want, diffs)
+	}
+}
+
 func TestAssertRepeated(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -176,6 +234,7 @@ func TestAssertRepeated(t *testing.T) {
 				Message:  "value mismatch",
 				Expected: `[<id:"1"> <id:"2"> <nil>]`,
 				Actual:   `<nil>`,
+				Path:     Path{fieldStep(outerFields.ByName("repeated_type"))},
 			},
 		},
 		{
@@ -186,10 +245,11 @@ func TestAssertRepeated(t *testing.T) {
 				}
 			}),
 			diff: &DiffError{
-				Field:    "repeated_type",
-				Message:  "length mismatch",
-				Expected: `3`,
-				Actual:   `1`,
+				Field:    "repeated_type.[0].id",
+				Message:  "value mismatch",
+				Expected: `"1"`,
+				Actual:   `"0"`,
+				Path:     Path{fieldStep(outerFields.ByName("repeated_type")), indexStep(0), fieldStep(innerFields.ByName("id"))},
 			},
 		},
 		{
@@ -206,6 +266,7 @@ func TestAssertRepeated(t *testing.T) {
 				Message:  "value mismatch",
 				Expected: `"2"`,
 				Actual:   `"3"`,
+				Path:     Path{fieldStep(outerFields.ByName("repeated_type")), indexStep(1), fieldStep(innerFields.ByName("id"))},
 			},
 		},
 		{
@@ -222,6 +283,7 @@ func TestAssertRepeated(t *testing.T) {
 				Message:  "value mismatch",
 				Expected: `<id:"2">`,
 				Actual:   `<nil>`,
+				Path:     Path{fieldStep(outerFields.ByName("repeated_type")), indexStep(1)},
 			},
 		},
 	}
@@ -263,9 +325,9 @@ func TestAssertRepeatedSimple(t *testing.T) {
 				v.RepeatedTypeSimple = []int32{1}
 			}),
 			diff: &DiffError{
-				Field:    "repeated_type_simple",
-				Message:  "length mismatch",
-				Expected: `3`,
+				Field:    "repeated_type_simple.[0]",
+				Message:  "value mismatch",
+				Expected: `9`,
 				Actual:   `1`,
 			},
 		},
@@ -296,6 +358,90 @@ func TestAssertRepeatedSimple(t *testing.T) {
 	}
 }
 
+func TestAssertListSequence(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   *sample.Outer
+		diffs   DiffErrors
+		inverse DiffErrors
+	}{
+		{
+			name: "scalar insertion",
+			input: makeInput(func(v *sample.Outer) {
+				v.RepeatedTypeSimple = []int32{9, 7, 10, 11}
+			}),
+			diffs: DiffErrors{
+				{Field: "repeated_type_simple.[1]", Message: "unexpected element", Expected: `<nil>`, Actual: `7`, Path: Path{fieldStep(outerFields.ByName("repeated_type_simple")), indexStep(1)}},
+			},
+			inverse: DiffErrors{
+				{Field: "repeated_type_simple.[1]", Message: "missing element", Expected: `7`, Actual: `<nil>`, Path: Path{fieldStep(outerFields.ByName("repeated_type_simple")), indexStep(1)}},
+			},
+		},
+		{
+			name: "scalar deletion and modification",
+			input: makeInput(func(v *sample.Outer) {
+				v.RepeatedTypeSimple = []int32{8}
+			}),
+			diffs: DiffErrors{
+				{Field: "repeated_type_simple.[0]", Message: "value mismatch", Expected: `9`, Actual: `8`, Path: Path{fieldStep(outerFields.ByName("repeated_type_simple")), indexStep(0)}},
+				{Field: "repeated_type_simple.[1]", Message: "missing element", Expected: `10`, Actual: `<nil>`, Path: Path{fieldStep(outerFields.ByName("repeated_type_simple")), indexStep(1)}},
+				{Field: "repeated_type_simple.[2]", Message: "missing element", Expected: `11`, Actual: `<nil>`, Path: Path{fieldStep(outerFields.ByName("repeated_type_simple")), indexStep(2)}},
+			},
+			inverse: DiffErrors{
+				{Field: "repeated_type_simple.[0]", Message: "value mismatch", Expected: `8`, Actual: `9`, Path: Path{fieldStep(outerFields.ByName("repeated_type_simple")), indexStep(0)}},
+				{Field: "repeated_type_simple.[1]", Message: "unexpected element", Expected: `<nil>`, Actual: `10`, Path: Path{fieldStep(outerFields.ByName("repeated_type_simple")), indexStep(1)}},
+				{Field: "repeated_type_simple.[2]", Message: "unexpected element", Expected: `<nil>`, Actual: `11`, Path: Path{fieldStep(outerFields.ByName("repeated_type_simple")), indexStep(2)}},
+			},
+		},
+		{
+			name: "message deletion",
+			input: makeInput(func(v *sample.Outer) {
+				v.RepeatedType = []*sample.Outer_Inner{
+					{Id: "1"},
+					nil,
+				}
+			}),
+			diffs: DiffErrors{
+				{Field: "repeated_type.[1]", Message: "missing element", Expected: `<id:"2">`, Actual: `<nil>`, Path: Path{fieldStep(outerFields.ByName("repeated_type")), indexStep(1)}},
+			},
+			inverse: DiffErrors{
+				{Field: "repeated_type.[1]", Message: "unexpected element", Expected: `<nil>`, Actual: `<id:"2">`, Path: Path{fieldStep(outerFields.ByName("repeated_type")), indexStep(1)}},
+			},
+		},
+		{
+			name: "message insertion and modification",
+			input: makeInput(func(v *sample.Outer) {
+				v.RepeatedType = []*sample.Outer_Inner{
+					{Id: "0"},
+					{Id: "1"},
+					{Id: "3"},
+					nil,
+				}
+			}),
+			diffs: DiffErrors{
+				{Field: "repeated_type.[0]", Message: "unexpected element", Expected: `<nil>`, Actual: `<id:"0">`, Path: Path{fieldStep(outerFields.ByName("repeated_type")), indexStep(0)}},
+				{Field: "repeated_type.[1].id", Message: "value mismatch", Expected: `"2"`, Actual: `"3"`, Path: Path{fieldStep(outerFields.ByName("repeated_type")), indexStep(1), fieldStep(innerFields.ByName("id"))}},
+			},
+			inverse: DiffErrors{
+				{Field: "repeated_type.[0]", Message: "missing element", Expected: `<id:"0">`, Actual: `<nil>`, Path: Path{fieldStep(outerFields.ByName("repeated_type")), indexStep(0)}},
+				{Field: "repeated_type.[2].id", Message: "value mismatch", Expected: `"3"`, Actual: `"2"`, Path: Path{fieldStep(outerFields.ByName("repeated_type")), indexStep(2), fieldStep(innerFields.ByName("id"))}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if diffs := EqualAll(makeInput(nil), tt.input); !reflect.DeepEqual(tt.diffs, withoutPaths(tt.diffs, diffs)) {
+				t.Errorf("mismatch:\nexpected: %v\nactual:   %v", tt.diffs, diffs)
+			}
+			if diffs := EqualAll(tt.input, makeInput(nil)); !reflect.DeepEqual(tt.inverse, withoutPaths(tt.inverse, diffs)) {
+				t.Errorf("(inverse) mismatch:\nexpected: %v\nactual:   %v", tt.inverse, diffs)
+			}
+		})
+	}
+}
+
 func TestAssertMap(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -312,18 +458,7 @@ func TestAssertMap(t *testing.T) {
 				Message:  "value mismatch",
 				Expected: `map[A:<id:"AA"> B:<id:"BB"> C:<nil>]`,
 				Actual:   `<nil>`,
-			},
-		},
-		{
-			name: "different length",
-			input: makeInput(func(v *sample.Outer) {
-				v.MapType["X"] = nil
-			}),
-			diff: &DiffError{
-				Field:    "map_type",
-				Message:  "length mismatch",
-				Expected: `3`,
-				Actual:   `4`,
+				Path:     Path{fieldStep(outerFields.ByName("map_type"))},
 			},
 		},
 		{
@@ -336,6 +471,7 @@ func TestAssertMap(t *testing.T) {
 				Message:  "value mismatch",
 				Expected: `"BB"`,
 				Actual:   `"XYZ"`,
+				Path:     Path{fieldStep(outerFields.ByName("map_type")), stringKeyStep("B"), fieldStep(innerFields.ByName("id"))},
 			},
 		},
 		{
@@ -348,6 +484,7 @@ func TestAssertMap(t *testing.T) {
 				Message:  "value mismatch",
 				Expected: `<id:"BB">`,
 				Actual:   `<nil>`,
+				Path:     Path{fieldStep(outerFields.ByName("map_type")), stringKeyStep("B")},
 			},
 		},
 	}
@@ -365,6 +502,76 @@ func TestAssertMap(t *testing.T) {
 	}
 }
 
+func TestAssertMapKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   *sample.Outer
+		diffs   DiffErrors
+		inverse DiffErrors
+	}{
+		{
+			name: "extra key",
+			input: makeInput(func(v *sample.Outer) {
+				v.MapType["X"] = &sample.Outer_Inner{Id: "XX"}
+			}),
+			diffs: DiffErrors{
+				{Field: "map_type.[X]", Message: "unexpected key", Expected: `<nil>`, Actual: `<id:"XX">`, Path: Path{fieldStep(outerFields.ByName("map_type")), stringKeyStep("X")}},
+			},
+			inverse: DiffErrors{
+				{Field: "map_type.[X]", Message: "missing key", Expected: `<id:"XX">`, Actual: `<nil>`, Path: Path{fieldStep(outerFields.ByName("map_type")), stringKeyStep("X")}},
+			},
+		},
+		{
+			name: "missing, extra and different keys",
+			input: makeInput(func(v *sample.Outer) {
+				delete(v.MapTypeSimple, "A")
+				v.MapTypeSimple["B"] = 99
+				v.MapTypeSimple["X"] = 0
+			}),
+			diffs: DiffErrors{
+				{Field: "map_type_simple.[A]", Message: "missing key", Expected: `20`, Actual: `<nil>`, Path: Path{fieldStep(outerFields.ByName("map_type_simple")), stringKeyStep("A")}},
+				{Field: "map_type_simple.[B]", Message: "value mismatch", Expected: `30`, Actual: `99`, Path: Path{fieldStep(outerFields.ByName("map_type_simple")), stringKeyStep("B")}},
+				{Field: "map_type_simple.[X]", Message: "unexpected key", Expected: `<nil>`, Actual: `0`, Path: Path{fieldStep(outerFields.ByName("map_type_simple")), stringKeyStep("X")}},
+			},
+			inverse: DiffErrors{
+				{Field: "map_type_simple.[A]", Message: "unexpected key", Expected: `<nil>`, Actual: `20`, Path: Path{fieldStep(outerFields.ByName("map_type_simple")), stringKeyStep("A")}},
+				{Field: "map_type_simple.[B]", Message: "value mismatch", Expected: `99`, Actual: `30`, Path: Path{fieldStep(outerFields.ByName("map_type_simple")), stringKeyStep("B")}},
+				{Field: "map_type_simple.[X]", Message: "missing key", Expected: `0`, Actual: `<nil>`, Path: Path{fieldStep(outerFields.ByName("map_type_simple")), stringKeyStep("X")}},
+			},
+		},
+		{
+			name: "interleaved missing and unexpected keys",
+			input: makeInput(func(v *sample.Outer) {
+				delete(v.MapTypeSimple, "B")
+				v.MapTypeSimple["AA"] = 1
+				v.MapTypeSimple["BA"] = 2
+			}),
+			diffs: DiffErrors{
+				{Field: "map_type_simple.[AA]", Message: "unexpected key", Expected: `<nil>`, Actual: `1`, Path: Path{fieldStep(outerFields.ByName("map_type_simple")), stringKeyStep("AA")}},
+				{Field: "map_type_simple.[B]", Message: "missing key", Expected: `30`, Actual: `<nil>`, Path: Path{fieldStep(outerFields.ByName("map_type_simple")), stringKeyStep("B")}},
+				{Field: "map_type_simple.[BA]", Message: "unexpected key", Expected: `<nil>`, Actual: `2`, Path: Path{fieldStep(outerFields.ByName("map_type_simple")), stringKeyStep("BA")}},
+			},
+			inverse: DiffErrors{
+				{Field: "map_type_simple.[AA]", Message: "missing key", Expected: `1`, Actual: `<nil>`, Path: Path{fieldStep(outerFields.ByName("map_type_simple")), stringKeyStep("AA")}},
+				{Field: "map_type_simple.[B]", Message: "unexpected key", Expected: `<nil>`, Actual: `30`, Path: Path{fieldStep(outerFields.ByName("map_type_simple")), stringKeyStep("B")}},
+				{Field: "map_type_simple.[BA]", Message: "missing key", Expected: `2`, Actual: `<nil>`, Path: Path{fieldStep(outerFields.ByName("map_type_simple")), stringKeyStep("BA")}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if diffs := EqualAll(makeInput(nil), tt.input); !reflect.DeepEqual(tt.diffs, withoutPaths(tt.diffs, diffs)) {
+				t.Errorf("mismatch:\nexpected: %v\nactual:   %v", tt.diffs, diffs)
+			}
+			if diffs := EqualAll(tt.input, makeInput(nil)); !reflect.DeepEqual(tt.inverse, withoutPaths(tt.inverse, diffs)) {
+				t.Errorf("(inverse) mismatch:\nexpected: %v\nactual:   %v", tt.inverse, diffs)
+			}
+		})
+	}
+}
+
 func TestAssertMapSimple(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -383,18 +590,6 @@ func TestAssertMapSimple(t *testing.T) {
 				Actual:   `<nil>`,
 			},
 		},
-		{
-			name: "different length",
-			input: makeInput(func(v *sample.Outer) {
-				v.MapTypeSimple["X"] = 0
-			}),
-			diff: &DiffError{
-				Field:    "map_type_simple",
-				Message:  "length mismatch",
-				Expected: `3`,
-				Actual:   `4`,
-			},
-		},
 		{
 			name: "different value",
 			input: makeInput(func(v *sample.Outer) {
@@ -489,6 +684,21 @@ func TestAssertOneOf(t *testing.T) {
 				Actual:   `"123"`,
 			},
 		},
+		{
+			name: "case mismatch",
+			expected: makeInput(func(v *sample.Outer) {
+				v.OneofType = &sample.Outer_OneofString{OneofString: "1"}
+			}),
+			input: makeInput(func(v *sample.Outer) {
+				v.OneofType = &sample.Outer_OneofMessage{OneofMessage: &sample.Outer_Inner{Id: "x"}}
+			}),
+			diff: &DiffError{
+				Field:    "oneof_type",
+				Message:  "case mismatch",
+				Expected: `oneof_string="1"`,
+				Actual:   `oneof_message=<id:"x">`,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -504,6 +714,25 @@ func TestAssertOneOf(t *testing.T) {
 	}
 }
 
+func TestEqualAllOneOfCaseMismatch(t *testing.T) {
+	diffs := EqualAll(
+		makeInput(nil),
+		makeInput(func(v *sample.Outer) {
+			v.OneofType = &sample.Outer_OneofMessage{OneofMessage: &sample.Outer_Inner{Id: "x"}}
+		}),
+	)
+	expected := DiffErrors{
+		{Field: "oneof_type", Message: "case mismatch", Expected: `oneof_string="1"`, Actual: `oneof_message=<id:"x">`},
+	}
+	if !reflect.DeepEqual(expected, withoutPaths(expected, diffs)) {
+		t.Errorf("mismatch:\nexpected: %v\nactual:   %v", expected, diffs)
+	}
+	expectedErr := "oneof_type: case mismatch\n+ oneof_string=\"1\"\n- oneof_message=<id:\"x\">"
+	if diffs.Error() != expectedErr {
+		t.Errorf("expected %s, got %s", expectedErr, diffs.Error())
+	}
+}
+
 func TestAssertTimestamp(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -518,7 +747,7 @@ func TestAssertTimestamp(t *testing.T) {
 			diff: &DiffError{
 				Field:    "timestamp_type",
 				Message:  "value mismatch",
-				Expected: `<seconds:1598814300>`,
+				Expected: `2020-08-30T19:05:00Z`,
 				Actual:   `<nil>`,
 			},
 		},
@@ -528,10 +757,10 @@ func TestAssertTimestamp(t *testing.T) {
 				v.TimestampType, _ = ptypes.TimestampProto(time.Date(2020, time.August, 30, 19, 05, 10, 00, time.UTC))
 			}),
 			diff: &DiffError{
-				Field:    "timestamp_type.seconds",
+				Field:    "timestamp_type",
 				Message:  "value mismatch",
-				Expected: `1598814300`,
-				Actual:   `1598814310`,
+				Expected: `2020-08-30T19:05:00Z`,
+				Actual:   `2020-08-30T19:05:10Z`,
 			},
 		},
 		{
@@ -540,10 +769,10 @@ func TestAssertTimestamp(t *testing.T) {
 				v.TimestampType, _ = ptypes.TimestampProto(time.Date(2020, time.August, 30, 19, 05, 00, 10, time.UTC))
 			}),
 			diff: &DiffError{
-				Field:    "timestamp_type.nanos",
+				Field:    "timestamp_type",
 				Message:  "value mismatch",
-				Expected: `0`,
-				Actual:   `10`,
+				Expected: `2020-08-30T19:05:00Z`,
+				Actual:   `2020-08-30T19:05:00.00000001Z`,
 			},
 		},
 	}
@@ -575,7 +804,7 @@ func TestAssertDuration(t *testing.T) {
 			diff: &DiffError{
 				Field:    "duration_type",
 				Message:  "value mismatch",
-				Expected: `<seconds:1>`,
+				Expected: `1s`,
 				Actual:   `<nil>`,
 			},
 		},
@@ -585,10 +814,10 @@ func TestAssertDuration(t *testing.T) {
 				v.DurationType = ptypes.DurationProto(2 * time.Second)
 			}),
 			diff: &DiffError{
-				Field:    "duration_type.seconds",
+				Field:    "duration_type",
 				Message:  "value mismatch",
-				Expected: `1`,
-				Actual:   `2`,
+				Expected: `1s`,
+				Actual:   `2s`,
 			},
 		},
 		{
@@ -597,10 +826,10 @@ func TestAssertDuration(t *testing.T) {
 				v.DurationType = ptypes.DurationProto(1005 * time.Millisecond)
 			}),
 			diff: &DiffError{
-				Field:    "duration_type.nanos",
+				Field:    "duration_type",
 				Message:  "value mismatch",
-				Expected: `0`,
-				Actual:   `5000000`,
+				Expected: `1s`,
+				Actual:   `1.005s`,
 			},
 		},
 	}
@@ -632,8 +861,9 @@ func TestAssertAny(t *testing.T) {
 			diff: &DiffError{
 				Field:    "any_type",
 				Message:  "value mismatch",
-				Expected: `<type_url:"mytype/v1" value:[5]>`,
+				Expected: `<type_url:"mytype/v1" value:"\x05">`,
 				Actual:   `<nil>`,
+				Path:     Path{fieldStep(outerFields.ByName("any_type"))},
 			},
 		},
 		{
@@ -646,6 +876,7 @@ func TestAssertAny(t *testing.T) {
 				Message:  "value mismatch",
 				Expected: `"mytype/v1"`,
 				Actual:   `"foo"`,
+				Path:     Path{fieldStep(outerFields.ByName("any_type")), fieldStep(anyFields.ByName("type_url"))},
 			},
 		},
 	}
@@ -708,6 +939,89 @@ func TestAssertNestedMessage(t *testing.T) {
 	}
 }
 
+func TestEqualAll(t *testing.T) {
+	expected := makeInput(nil)
+	actual := makeInput(func(v *sample.Outer) {
+		v.StrVal = "invalid"
+		v.RepeatedType[1].Id = "3"
+		v.MapTypeSimple["B"] = 99
+		v.NestedMessage = nil
+	})
+
+	expectedErrs := DiffErrors{
+		{
+			Field:    "str_val",
+			Message:  "value mismatch",
+			Expected: `"foo"`,
+			Actual:   `"invalid"`,
+		},
+		{
+			Field:    "repeated_type.[1].id",
+			Message:  "value mismatch",
+			Expected: `"2"`,
+			Actual:   `"3"`,
+		},
+		{
+			Field:    "map_type_simple.[B]",
+			Message:  "value mismatch",
+			Expected: `30`,
+			Actual:   `99`,
+		},
+		{
+			Field:    "nested_message",
+			Message:  "value mismatch",
+			Expected: `<inner:<id:"123">>`,
+			Actual:   `<nil>`,
+		},
+	}
+
+	actualErrs := withoutPaths(expectedErrs, EqualAll(expected, actual))
+	if !reflect.DeepEqual(expectedErrs, actualErrs) {
+		t.Errorf("mismatch err\n++ want:\n%s\n-- got:\n%s", expectedErrs, actualErrs)
+	}
+
+	if errs := EqualAll(makeInput(nil), makeInput(nil)); errs != nil {
+		t.Errorf("mismatch err\n++ want:\n%v\n-- got:\n%s", nil, errs)
+	}
+}
+
+func TestEqualAllOrder(t *testing.T) {
+	expected := makeInput(func(v *sample.Outer) {
+		v.NestedMessage = nil
+		v.WrappedBool = wrapperspb.Bool(false)
+	})
+	actual := makeInput(func(v *sample.Outer) {
+		v.BoolVal = false
+		v.RepeatedTypeSimple = []int32{0, 10, 0}
+		v.MapTypeSimple = map[string]int32{"A": 0, "B": 0, "C": 0}
+		v.NestedMessage = &sample.Outer_NestedInner{}
+		v.WrappedBool = wrapperspb.Bool(true)
+		v.StrVal = "bar"
+	})
+
+	expectedFields := []string{
+		"str_val",
+		"bool_val",
+		"repeated_type_simple.[0]",
+		"repeated_type_simple.[2]",
+		"map_type_simple.[A]",
+		"map_type_simple.[B]",
+		"map_type_simple.[C]",
+		"wrapped_bool",
+		"nested_message",
+	}
+
+	for i := 0; i < 20; i++ {
+		var got []string
+		for _, d := range EqualAll(expected, actual) {
+			got = append(got, d.Field)
+		}
+		if !reflect.DeepEqual(expectedFields, got) {
+			t.Fatalf("mismatch order\n++ want:\n%v\n-- got:\n%v", expectedFields, got)
+		}
+	}
+}
+
 func makeInput(f func(v *sample.Outer)) *sample.Outer {
 	v := &sample.Outer{
 		StrVal:    "foo",
@@ -749,8 +1063,8 @@ func makeInput(f func(v *sample.Outer)) *sample.Outer {
 	return v
 }
 
-func check(t *testing.T, expected *sample.Outer, actual *sample.Outer, expectedErr *DiffError) {
-	actualErr := Equal(expected, actual)
+func check(t *testing.T, expected *sample.Outer, actual *sample.Outer, expectedErr *DiffError, opts ...Option) {
+	actualErr := withoutPath(expectedErr, Equal(expected, actual, opts...))
 	if !reflect.DeepEqual(expectedErr, actualErr) {
 		t.Errorf("mismatch err\n++ want:\n%s\n-- got:\n%s", expectedErr, actualErr)
 		return
@@ -763,8 +1077,125 @@ func check(t *testing.T, expected *sample.Outer, actual *sample.Outer, expectedE
 		expectedErr.Expected = x
 	}
 
-	actualErr = Equal(actual, expected)
+	actualErr = withoutPath(expectedErr, Equal(actual, expected, opts...))
 	if !reflect.DeepEqual(expectedErr, actualErr) {
 		t.Errorf("(inverse) mismatch err\n++ want:\n%s\n-- got:\n%s", expectedErr, actualErr)
 	}
 }
+
+// withoutPath returns a copy of the diff without its typed path if the
+// expected diff has none, so that expectations may only name the Field.
+func withoutPath(want, d *DiffError) *DiffError {
+	if d == nil || (want != nil && want.Path != nil) {
+		return d
+	}
+	c := *d
+	c.Path = nil
+	return &c
+}
+
+func withoutPaths(want, d DiffErrors) DiffErrors {
+	if d == nil {
+		return nil
+	}
+	c := make(DiffErrors, len(d))
+	for i, e := range d {
+		var w *DiffError
+		if i < len(want) {
+			w = want[i]
+		}
+		c[i] = withoutPath(w, e)
+	}
+	return c
+}
+
+// Field descriptors used to build the expected paths of diffs.
+var (
+	outerFields = (&sample.Outer{}).ProtoReflect().Descriptor().Fields()
+	innerFields = (&sample.Outer_Inner{}).ProtoReflect().Descriptor().Fields()
+	anyFields   = (&any.Any{}).ProtoReflect().Descriptor().Fields()
+)
+
+// stringKeyStep returns the path step to the entry of a map with the given key.
+func stringKeyStep(k string) PathStep {
+	return mapKeyStep(protoreflect.ValueOfString(k).MapKey())
+}
+
+func TestAssertDescriptorInstances(t *testing.T) {
+	dynamic := func(fdp *descriptorpb.FileDescriptorProto, m *sample.Outer) *dynamicpb.Message {
+		fd, err := protodesc.NewFile(fdp, protoregistry.GlobalFiles)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := proto.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dm := dynamicpb.NewMessage(fd.Messages().ByName("Outer"))
+		if err := proto.Unmarshal(b, dm); err != nil {
+			t.Fatal(err)
+		}
+		return dm
+	}
+
+	sameSchema := protodesc.ToFileDescriptorProto(sample.File_sample_proto)
+	otherSchema := protodesc.ToFileDescriptorProto(sample.File_sample_proto)
+	for _, f := range otherSchema.MessageType[0].Field {
+		if f.GetName() == "nested_message" {
+			f.TypeName = proto.String(".sample.Outer.Inner")
+		}
+	}
+
+	// nil elements are decoded as empty messages.
+	expected := func(f func(v *sample.Outer)) *sample.Outer {
+		return makeInput(func(v *sample.Outer) {
+			v.RepeatedType[2] = &sample.Outer_Inner{}
+			v.MapType["C"] = &sample.Outer_Inner{}
+			if f != nil {
+				f(v)
+			}
+		})
+	}
+
+	tests := []struct {
+		name  string
+		input proto.Message
+		diff  *DiffError
+	}{
+		{
+			name:  "same schema",
+			input: dynamic(sameSchema, expected(nil)),
+		},
+		{
+			name: "same schema - different value",
+			input: dynamic(sameSchema, expected(func(v *sample.Outer) {
+				v.RepeatedType[1].Id = "3"
+			})),
+			diff: &DiffError{
+				Field:    "repeated_type.[1].id",
+				Message:  "value mismatch",
+				Expected: `"2"`,
+				Actual:   `"3"`,
+			},
+		},
+		{
+			name:  "different message type",
+			input: dynamic(otherSchema, expected(nil)),
+			diff: &DiffError{
+				Field:    "nested_message",
+				Message:  "descriptors don't match",
+				Expected: `sample.Outer.NestedInner`,
+				Actual:   `sample.Outer.Inner`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if actualErr := withoutPath(tt.diff, Equal(expected(nil), tt.input)); !reflect.DeepEqual(tt.diff, actualErr) {
+				t.Errorf("mismatch err\n++ want:\n%s\n-- got:\n%s", tt.diff, actualErr)
+			}
+		})
+	}
+}
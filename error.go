@@ -8,18 +8,46 @@ import (
 )
 
 type DiffError struct {
+	// Field is the location of the difference as a dot separated string, e.g.
+	// "repeated_type.[1].id". Path holds the same location as typed steps, and
+	// is empty for a difference of the root message.
 	Field    string
 	Message  string
 	Expected string
 	Actual   string
+	// Diff is a line-based unified diff of long or multi-line string values,
+	// printed by Error instead of Expected and Actual.
+	Diff string
+	Path Path
 }
 
 func (d *DiffError) Error() string {
-	return fmt.Sprintf("%s: %s\n+ %s\n- %s", d.Field, d.Message, d.Expected, d.Actual)
+	if d.Diff != "" {
+		return fmt.Sprintf("%s: %s\n%s", d.Field, d.Message, d.Diff)
+	}
+	return fmt.Sprintf("%s: %s\n%s\n%s", d.Field, d.Message, markLines("+ ", d.Expected), markLines("- ", d.Actual))
+}
+
+// markLines prefixes every line of a value with the marker of its side, so
+// that each line of a multi-line value such as a hex dump reads as part of it.
+func markLines(marker, s string) string {
+	return marker + strings.ReplaceAll(s, "\n", "\n"+marker)
+}
+
+// DiffErrors is a collection of differences, one per mismatching path.
+type DiffErrors []*DiffError
+
+func (d DiffErrors) Error() string {
+	s := make([]string, len(d))
+	for i, e := range d {
+		s[i] = e.Error()
+	}
+	return strings.Join(s, "\n")
 }
 
 type matchErr struct {
 	fieldKeys []string
+	path      Path
 	message   string
 	expected  interface{}
 	actual    interface{}
@@ -29,11 +57,20 @@ func newMatchError(message string) *matchErr {
 	return &matchErr{message: message}
 }
 
+// Field prepends the key to the field of the error without a path step, as
+// for the name of the root message.
 func (m *matchErr) Field(k protoreflect.Name) *matchErr {
 	m.fieldKeys = append([]string{string(k)}, m.fieldKeys...)
 	return m
 }
 
+// Step prepends the step to the path of the error.
+func (m *matchErr) Step(s PathStep) *matchErr {
+	m.fieldKeys = append([]string{s.key()}, m.fieldKeys...)
+	m.path = append(Path{s}, m.path...)
+	return m
+}
+
 func (m *matchErr) Values(expected, actual interface{}) *matchErr {
 	m.expected = expected
 	m.actual = actual
@@ -58,14 +95,38 @@ func (m *matchErr) ValueExpected(expected interface{}) *matchErr {
 }
 
 func (m *matchErr) Diff() *DiffError {
-	return &DiffError{
+	d := &DiffError{
 		Field:    strings.Join(m.fieldKeys, "."),
 		Message:  m.message,
 		Expected: fmt.Sprintf("%v", m.expected),
 		Actual:   fmt.Sprintf("%v", m.actual),
+		Path:     m.path,
 	}
+	x, xok := m.expected.(text)
+	y, yok := m.actual.(text)
+	if xok && yok && (isLongText(string(x)) || isLongText(string(y))) {
+		d.Diff = unifiedDiff(string(x), string(y))
+	}
+	return d
 }
 
 func (m *matchErr) Error() string {
 	return m.Diff().Error()
 }
+
+type matchErrs []*matchErr
+
+func (m matchErrs) Step(s PathStep) matchErrs {
+	for _, e := range m {
+		e.Step(s)
+	}
+	return m
+}
+
+func (m matchErrs) Diff() DiffErrors {
+	d := make(DiffErrors, len(m))
+	for i, e := range m {
+		d[i] = e.Diff()
+	}
+	return d
+}
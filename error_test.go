@@ -26,3 +26,29 @@ func TestMatchErrReturnsDiffError(t *testing.T) {
 	}
 
 }
+
+func TestDiffErrorsError(t *testing.T) {
+	errs := DiffErrors{
+		{Field: "foo", Message: "value mismatch", Expected: "1", Actual: "2"},
+		{Field: "bar", Message: "length mismatch", Expected: "3", Actual: "4"},
+	}
+
+	expected := "foo: value mismatch\n+ 1\n- 2\nbar: length mismatch\n+ 3\n- 4"
+	if actual := errs.Error(); expected != actual {
+		t.Errorf("mismatch: want %q, got %q", expected, actual)
+	}
+}
+
+func TestDiffErrorMultiLine(t *testing.T) {
+	err := &DiffError{
+		Field:    "bytes_val",
+		Message:  "value mismatch",
+		Expected: "...\n00000010  01 |.|",
+		Actual:   "...\n00000010  02 |.|",
+	}
+
+	expected := "bytes_val: value mismatch\n+ ...\n+ 00000010  01 |.|\n- ...\n- 00000010  02 |.|"
+	if actual := err.Error(); expected != actual {
+		t.Errorf("mismatch: want %q, got %q", expected, actual)
+	}
+}
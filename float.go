@@ -0,0 +1,136 @@
+package protocmp
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+type toleranceKind int
+
+const (
+	absTolerance toleranceKind = iota
+	relTolerance
+	ulpTolerance
+)
+
+// floatTolerance is the maximum accepted distance between two floating point
+// values, optionally restricted to a set of field paths.
+type floatTolerance struct {
+	kind  toleranceKind
+	value float64
+	paths []fieldPath
+}
+
+// appliesTo reports whether the tolerance is restricted to the given path.
+func (t floatTolerance) appliesTo(p fieldPath) bool {
+	return matchesAny(p, t.paths) || matchesAny(p.field(), t.paths)
+}
+
+// toleratedFloat reports whether the field at the given path is a singular
+// float or double field with a tolerance, so that a value set on one side only
+// is compared with the zero value of the unset side.
+func toleratedFloat(opts *options, p fieldPath, fd protoreflect.FieldDescriptor) bool {
+	if fd.IsList() || fd.IsMap() {
+		return false
+	}
+	if fd.Kind() != protoreflect.FloatKind && fd.Kind() != protoreflect.DoubleKind {
+		return false
+	}
+	return len(opts.floatTolerances(p)) > 0
+}
+
+// within reports whether x and y are within the tolerance, along with a
+// description of the distance when they are not.
+func (t floatTolerance) within(kind protoreflect.Kind, x, y float64) (bool, string) {
+	switch t.kind {
+	case relTolerance:
+		delta := math.Abs(x-y) / math.Max(math.Abs(x), math.Abs(y))
+		if delta <= t.value {
+			return true, ""
+		}
+		return false, fmt.Sprintf("relative delta %v exceeds tolerance %v", delta, t.value)
+	case ulpTolerance:
+		delta := ulpDistance(kind, x, y)
+		if float64(delta) <= t.value {
+			return true, ""
+		}
+		return false, fmt.Sprintf("delta %d ULP exceeds tolerance %v ULP", delta, t.value)
+	default:
+		delta := math.Abs(x - y)
+		if delta <= t.value {
+			return true, ""
+		}
+		return false, fmt.Sprintf("delta %v exceeds tolerance %v", delta, t.value)
+	}
+}
+
+// ulpDistance returns the number of representable values between x and y in
+// the precision of the field kind.
+func ulpDistance(kind protoreflect.Kind, x, y float64) uint64 {
+	var ix, iy int64
+	if kind == protoreflect.FloatKind {
+		ix = int64(orderedBits32(float32(x)))
+		iy = int64(orderedBits32(float32(y)))
+	} else {
+		ix = orderedBits64(x)
+		iy = orderedBits64(y)
+	}
+	if ix > iy {
+		return uint64(ix) - uint64(iy)
+	}
+	return uint64(iy) - uint64(ix)
+}
+
+// orderedBits64 maps a float64 onto an integer so that adjacent floats map to
+// adjacent integers.
+func orderedBits64(f float64) int64 {
+	i := int64(math.Float64bits(f))
+	if i < 0 {
+		return math.MinInt64 - i
+	}
+	return i
+}
+
+// orderedBits32 maps a float32 onto an integer so that adjacent floats map to
+// adjacent integers.
+func orderedBits32(f float32) int32 {
+	i := int32(math.Float32bits(f))
+	if i < 0 {
+		return math.MinInt32 - i
+	}
+	return i
+}
+
+// equalFloat compares two floating point values. Values outside of the
+// tolerances configured for their path are reported along with the distance.
+func equalFloat(opts *options, p fieldPath, fd protoreflect.FieldDescriptor, fx, fy float64) matchErrs {
+	if math.IsNaN(fx) || math.IsNaN(fy) {
+		if math.IsNaN(fx) != math.IsNaN(fy) {
+			return matchErrs{newMatchError("value mismatch").Values(fx, fy)}
+		}
+
+		return nil
+	}
+	if fx == fy {
+		return nil
+	}
+
+	tolerances := opts.floatTolerances(p)
+	if len(tolerances) == 0 {
+		return matchErrs{newMatchError("value mismatch").Values(fx, fy)}
+	}
+
+	reasons := make([]string, 0, len(tolerances))
+	for _, t := range tolerances {
+		ok, reason := t.within(fd.Kind(), fx, fy)
+		if ok {
+			return nil
+		}
+		reasons = append(reasons, reason)
+	}
+
+	return matchErrs{newMatchError(fmt.Sprintf("value mismatch (%s)", strings.Join(reasons, ", "))).Values(fx, fy)}
+}
@@ -0,0 +1,169 @@
+package protocmp
+
+import (
+	"math"
+	"testing"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/nbaztec/protocmp/protos/sample"
+)
+
+func TestFloatTolerance(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected *sample.Outer
+		input    *sample.Outer
+		opt      Option
+		diff     *DiffError
+	}{
+		{
+			name: "absolute - within",
+			input: makeInput(func(v *sample.Outer) {
+				v.DoubleVal = 1.1000001
+			}),
+			opt: FloatAbsTolerance(1e-6),
+		},
+		{
+			name: "absolute - exceeded",
+			input: makeInput(func(v *sample.Outer) {
+				v.DoubleVal = 1.5
+			}),
+			opt: FloatAbsTolerance(0.25),
+			diff: &DiffError{
+				Field:    "double_val",
+				Message:  "value mismatch (delta 0.3999999999999999 exceeds tolerance 0.25)",
+				Expected: `1.1`,
+				Actual:   `1.5`,
+			},
+		},
+		{
+			name: "relative - within",
+			input: makeInput(func(v *sample.Outer) {
+				v.DoubleVal = 1.2
+			}),
+			opt: FloatRelTolerance(0.1),
+		},
+		{
+			name: "relative - exceeded",
+			input: makeInput(func(v *sample.Outer) {
+				v.DoubleVal = 2.2
+			}),
+			opt: FloatRelTolerance(0.1),
+			diff: &DiffError{
+				Field:    "double_val",
+				Message:  "value mismatch (relative delta 0.5 exceeds tolerance 0.1)",
+				Expected: `1.1`,
+				Actual:   `2.2`,
+			},
+		},
+		{
+			name: "ulp - within",
+			input: makeInput(func(v *sample.Outer) {
+				v.DoubleVal = math.Nextafter(math.Nextafter(1.1, 2), 2)
+			}),
+			opt: FloatULPTolerance(2),
+		},
+		{
+			name: "ulp - exceeded",
+			input: makeInput(func(v *sample.Outer) {
+				v.DoubleVal = math.Nextafter(math.Nextafter(1.1, 2), 2)
+			}),
+			opt: FloatULPTolerance(1),
+			diff: &DiffError{
+				Field:    "double_val",
+				Message:  "value mismatch (delta 2 ULP exceeds tolerance 1 ULP)",
+				Expected: `1.1`,
+				Actual:   `1.1000000000000005`,
+			},
+		},
+		{
+			name: "absolute - zero within",
+			expected: makeInput(func(v *sample.Outer) {
+				v.DoubleVal = 0
+			}),
+			input: makeInput(func(v *sample.Outer) {
+				v.DoubleVal = 1e-12
+			}),
+			opt: FloatAbsTolerance(1e-9),
+		},
+		{
+			name: "absolute - zero exceeded",
+			expected: makeInput(func(v *sample.Outer) {
+				v.DoubleVal = 0
+			}),
+			input: makeInput(func(v *sample.Outer) {
+				v.DoubleVal = 0.5
+			}),
+			opt: FloatAbsTolerance(0.25),
+			diff: &DiffError{
+				Field:    "double_val",
+				Message:  "value mismatch (delta 0.5 exceeds tolerance 0.25)",
+				Expected: `0`,
+				Actual:   `0.5`,
+			},
+		},
+		{
+			name: "path - within",
+			input: makeInput(func(v *sample.Outer) {
+				v.DoubleVal = 1.2
+			}),
+			opt: FloatAbsTolerance(0.5, "double_val"),
+		},
+		{
+			name: "path - other field",
+			input: makeInput(func(v *sample.Outer) {
+				v.DoubleVal = 1.2
+			}),
+			opt: FloatAbsTolerance(0.5, "nested_message.value"),
+			diff: &DiffError{
+				Field:    "double_val",
+				Message:  "value mismatch",
+				Expected: `1.1`,
+				Actual:   `1.2`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			expected := tt.expected
+			if expected == nil {
+				expected = makeInput(nil)
+			}
+			check(
+				t,
+				expected,
+				tt.input,
+				tt.diff,
+				tt.opt,
+			)
+		})
+	}
+}
+
+func TestUlpDistance(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     protoreflect.Kind
+		x        float64
+		y        float64
+		expected uint64
+	}{
+		{name: "equal", kind: protoreflect.DoubleKind, x: 1, y: 1, expected: 0},
+		{name: "adjacent", kind: protoreflect.DoubleKind, x: 1, y: math.Nextafter(1, 2), expected: 1},
+		{name: "signed zeros", kind: protoreflect.DoubleKind, x: math.Copysign(0, -1), y: 0, expected: 0},
+		{name: "across zero", kind: protoreflect.DoubleKind, x: -math.SmallestNonzeroFloat64, y: math.SmallestNonzeroFloat64, expected: 2},
+		{name: "float adjacent", kind: protoreflect.FloatKind, x: 1, y: float64(math.Nextafter32(1, 2)), expected: 1},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := ulpDistance(tt.kind, tt.x, tt.y); tt.expected != actual {
+				t.Errorf("mismatch: want %d, got %d", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -8,10 +8,6 @@ import (
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
-func quoteString(v interface{}) string {
-	return strconv.Quote(fmt.Sprintf("%s", v))
-}
-
 func fmtMessage(m protoreflect.Message) string {
 	f := &formatter{}
 	f.printMessage(m)
@@ -30,6 +26,18 @@ func fmtMap(mmap protoreflect.Map, fd protoreflect.FieldDescriptor) string {
 	return f.String()
 }
 
+func fmtValue(v protoreflect.Value, fd protoreflect.FieldDescriptor) string {
+	f := &formatter{}
+	f.printSingular(v, fd)
+	return f.String()
+}
+
+func fmtKey(v protoreflect.Value, fd protoreflect.FieldDescriptor) string {
+	f := &formatter{}
+	f.printSingularKey(v, fd)
+	return f.String()
+}
+
 type formatter struct {
 	str string
 }
@@ -54,6 +62,21 @@ func (f *formatter) trimAndPrint(v interface{}) {
 }
 
 func (f *formatter) printMessage(m protoreflect.Message) {
+	if isTimeMessage(m.Descriptor()) && m.IsValid() {
+		f.print(fmtTime(m))
+		return
+	}
+
+	if isWrapperMessage(m.Descriptor()) {
+		f.print(fmtWrapper(m))
+		return
+	}
+
+	if isStructMessage(m.Descriptor()) && m.IsValid() {
+		f.print(fmtJSON(structValue(m)))
+		return
+	}
+
 	f.print("<")
 	defer f.trimAndPrint(">")
 	
@@ -150,10 +173,12 @@ func (f *formatter) printSingularWrapped(val protoreflect.Value, fd protoreflect
 		protoreflect.Uint32Kind, protoreflect.Uint64Kind,
 		protoreflect.Fixed32Kind, protoreflect.Fixed64Kind,
 		protoreflect.FloatKind,
-		protoreflect.DoubleKind,
-		protoreflect.BytesKind:
+		protoreflect.DoubleKind:
 		f.print(val)
 
+	case protoreflect.BytesKind:
+		f.print(strconv.Quote(string(val.Bytes())))
+
 	case protoreflect.EnumKind:
 		num := val.Enum()
 		if desc := fd.Enum().Values().ByNumber(num); desc != nil {
@@ -164,7 +189,7 @@ func (f *formatter) printSingularWrapped(val protoreflect.Value, fd protoreflect
 		}
 
 	case protoreflect.MessageKind, protoreflect.GroupKind:
-		if !val.Message().IsValid() {
+		if !val.Message().IsValid() && !isWrapperMessage(val.Message().Descriptor()) {
 			f.print("<nil>")
 			return
 		}
@@ -0,0 +1,113 @@
+package protocmp
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// FieldMask is a set of field paths, such as a *fieldmaskpb.FieldMask.
+type FieldMask interface {
+	GetPaths() []string
+}
+
+// Paths is a FieldMask made of a plain list of field paths.
+type Paths []string
+
+func (p Paths) GetPaths() []string {
+	return p
+}
+
+// EqualMasked compares only the fields at the paths of the mask, along with
+// all of their subfields, and returns the first difference found. Paths are
+// dot separated field names relative to the root message, e.g.
+// "nested_message.inner". An error is returned if a path does not resolve
+// against the message descriptor or goes through a repeated or map field, and
+// if the mask is nil or has no paths. Unknown fields are not compared.
+func EqualMasked(x, y proto.Message, mask FieldMask, opts ...Option) error {
+	if mask == nil || len(mask.GetPaths()) == 0 {
+		return errors.New("invalid mask: no paths")
+	}
+
+	paths, err := resolveMask(x, y, mask.GetPaths())
+	if err != nil {
+		return err
+	}
+
+	if err := Equal(x, y, append(opts, maskPaths(paths))...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// maskPaths limits the comparison to the fields at the given paths.
+func maskPaths(paths []fieldPath) Option {
+	return func(o *options) {
+		o.mask = append(o.mask, paths...)
+	}
+}
+
+// resolveMask parses the mask paths and checks that they resolve against the
+// descriptor of the messages.
+func resolveMask(x, y proto.Message, paths []string) ([]fieldPath, error) {
+	var md protoreflect.MessageDescriptor
+	for _, m := range []proto.Message{x, y} {
+		if v := reflect.ValueOf(m); v.IsValid() {
+			md = proto.MessageReflect(m).Descriptor()
+			break
+		}
+	}
+
+	mask := make([]fieldPath, 0, len(paths))
+	for _, s := range paths {
+		p := fieldPath(strings.Split(s, "."))
+		if md != nil {
+			if err := resolvePath(md, p); err != nil {
+				return nil, fmt.Errorf("invalid mask path %q: %s", s, err)
+			}
+		}
+		mask = append(mask, p)
+	}
+
+	return mask, nil
+}
+
+// resolvePath checks that every step of the path names a field, and that every
+// step but the last one names a singular message field.
+func resolvePath(md protoreflect.MessageDescriptor, p fieldPath) error {
+	for i, step := range p {
+		if md == nil {
+			return fmt.Errorf("field %q is not a message", p[i-1])
+		}
+		fd := md.Fields().ByName(protoreflect.Name(step))
+		if fd == nil {
+			return fmt.Errorf("%s has no field %q", md.FullName(), step)
+		}
+		if i < len(p)-1 && (fd.IsList() || fd.IsMap()) {
+			return fmt.Errorf("field %q is repeated", step)
+		}
+		md = fd.Message()
+	}
+	return nil
+}
+
+// maskedOut reports whether the field at the given path lies outside of the
+// mask, i.e. it is neither on the way to a masked field nor below one.
+func (o *options) maskedOut(p fieldPath) bool {
+	if len(o.mask) == 0 {
+		return false
+	}
+
+	names := p.names()
+	for _, m := range o.mask {
+		if names.hasPrefix(m) || m.hasPrefix(names) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,154 @@
+package protocmp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nbaztec/protocmp/protos/sample"
+)
+
+func TestEqualMasked(t *testing.T) {
+	tests := []struct {
+		name  string
+		input *sample.Outer
+		mask  Paths
+		err   error
+	}{
+		{
+			name: "unmasked field",
+			input: makeInput(func(v *sample.Outer) {
+				v.StrVal = "invalid"
+				v.NestedMessage = nil
+			}),
+			mask: Paths{"int_val", "repeated_type"},
+		},
+		{
+			name: "masked field",
+			input: makeInput(func(v *sample.Outer) {
+				v.StrVal = "invalid"
+			}),
+			mask: Paths{"int_val", "str_val"},
+			err: &DiffError{
+				Field:    "str_val",
+				Message:  "value mismatch",
+				Expected: `"foo"`,
+				Actual:   `"invalid"`,
+			},
+		},
+		{
+			name: "masked subfield",
+			input: makeInput(func(v *sample.Outer) {
+				v.NestedMessage.Inner.Id = "foo"
+			}),
+			mask: Paths{"nested_message.inner.id"},
+			err: &DiffError{
+				Field:    "nested_message.inner.id",
+				Message:  "value mismatch",
+				Expected: `"123"`,
+				Actual:   `"foo"`,
+			},
+		},
+		{
+			name: "unknown fields",
+			input: makeInput(func(v *sample.Outer) {
+				v.ProtoReflect().SetUnknown(appendUnknownVarint(nil, 17, 42))
+			}),
+			mask: Paths{"str_val"},
+		},
+		{
+			name: "masked parent",
+			input: makeInput(func(v *sample.Outer) {
+				v.NestedMessage.Inner.Id = "foo"
+			}),
+			mask: Paths{"nested_message"},
+			err: &DiffError{
+				Field:    "nested_message.inner.id",
+				Message:  "value mismatch",
+				Expected: `"123"`,
+				Actual:   `"foo"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := EqualMasked(makeInput(nil), tt.input, tt.mask)
+			if d, ok := err.(*DiffError); ok {
+				want, _ := tt.err.(*DiffError)
+				err = withoutPath(want, d)
+			}
+			if !reflect.DeepEqual(tt.err, err) {
+				t.Errorf("mismatch err\n++ want:\n%v\n-- got:\n%v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestEqualMaskedInvalidPath(t *testing.T) {
+	tests := []struct {
+		name string
+		mask Paths
+		err  string
+	}{
+		{
+			name: "unknown field",
+			mask: Paths{"foo"},
+			err:  `invalid mask path "foo": sample.Outer has no field "foo"`,
+		},
+		{
+			name: "unknown subfield",
+			mask: Paths{"nested_message.inner.foo"},
+			err:  `invalid mask path "nested_message.inner.foo": sample.Outer.NestedInner.Inner has no field "foo"`,
+		},
+		{
+			name: "scalar parent",
+			mask: Paths{"str_val.foo"},
+			err:  `invalid mask path "str_val.foo": field "str_val" is not a message`,
+		},
+		{
+			name: "repeated parent",
+			mask: Paths{"repeated_type.id"},
+			err:  `invalid mask path "repeated_type.id": field "repeated_type" is repeated`,
+		},
+		{
+			name: "map parent",
+			mask: Paths{"map_type.foo"},
+			err:  `invalid mask path "map_type.foo": field "map_type" is repeated`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := EqualMasked(makeInput(nil), makeInput(nil), tt.mask)
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("mismatch err: want %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestEqualMaskedEmpty(t *testing.T) {
+	tests := []struct {
+		name string
+		mask FieldMask
+	}{
+		{name: "nil", mask: nil},
+		{name: "nil paths", mask: Paths(nil)},
+		{name: "no paths", mask: Paths{}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			input := makeInput(func(v *sample.Outer) {
+				v.StrVal = "invalid"
+			})
+			err := EqualMasked(makeInput(nil), input, tt.mask)
+			if err == nil || err.Error() != "invalid mask: no paths" {
+				t.Errorf("mismatch err: want %q, got %v", "invalid mask: no paths", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package protocmp
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// equalOneofs returns a case mismatch, e.g. "oneof_type: case mismatch" with
+// the values oneof_string="1" and oneof_message=<id:"x">, for every oneof for
+// which mx and my have different fields set, keyed by the name of the oneof.
+// The fields of these oneofs are not compared one by one.
+func equalOneofs(opts *options, p fieldPath, mx, my protoreflect.Message) map[protoreflect.Name]*matchErr {
+	var mismatched map[protoreflect.Name]*matchErr
+
+	oneofs := mx.Descriptor().Oneofs()
+	for i := 0; i < oneofs.Len(); i++ {
+		od := oneofs.Get(i)
+		ody := my.Descriptor().Oneofs().ByName(od.Name())
+		if od.IsSynthetic() || ody == nil {
+			continue
+		}
+
+		fx, fy := mx.WhichOneof(od), my.WhichOneof(ody)
+		if fx == nil || fy == nil || fx.Number() == fy.Number() {
+			continue
+		}
+		if opts.ignored(fx, p.push(string(fx.Name()))) || opts.ignored(fy, p.push(string(fy.Name()))) {
+			continue
+		}
+
+		if mismatched == nil {
+			mismatched = make(map[protoreflect.Name]*matchErr)
+		}
+		mismatched[od.Name()] = newMatchError("case mismatch").Step(oneofStep(od, fx)).Values(fmtOneof(fx, mx.Get(fx)), fmtOneof(fy, my.Get(fy)))
+	}
+	return mismatched
+}
+
+// fmtOneof renders the field set in a oneof with its value, e.g. oneof_string="1".
+func fmtOneof(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	return fmt.Sprintf("%s=%s", fd.Name(), fmtValue(v, fd))
+}
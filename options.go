@@ -0,0 +1,361 @@
+package protocmp
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Option configures how two messages are compared.
+type Option func(*options)
+
+type options struct {
+	ignoreNames map[protoreflect.FullName]bool
+	ignorePaths []fieldPath
+
+	ignoreUnknown    bool
+	unorderedUnknown bool
+
+	tolerances     []floatTolerance
+	timeTolerances []timeTolerance
+
+	unorderedAll   bool
+	unorderedPaths []fieldPath
+
+	listKeys []listKey
+
+	resolver protoregistry.MessageTypeResolver
+
+	bytesFormat BytesFormat
+
+	equateEmpty       bool
+	equateWrapperZero bool
+	partial           bool
+
+	comparers map[protoreflect.FullName]func(x, y protoreflect.Message) bool
+
+	transforms  []transformer
+	sortedPaths []fieldPath
+
+	mask []fieldPath
+}
+
+// listKey is the field used to pair the elements of the repeated message
+// fields at path.
+type listKey struct {
+	path fieldPath
+	key  protoreflect.Name
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// IgnoreFields skips the given fields on both sides of the comparison. A field
+// is either a fully-qualified field name such as "sample.Outer.timestamp_type",
+// or a path relative to the root message such as "nested_message.inner.id",
+// "repeated_type[*].id", "map_type[A]" or `map_type["A"]`.
+func IgnoreFields(names ...string) Option {
+	return func(o *options) {
+		if o.ignoreNames == nil {
+			o.ignoreNames = make(map[protoreflect.FullName]bool)
+		}
+		for _, name := range names {
+			o.ignoreNames[protoreflect.FullName(name)] = true
+			o.ignorePaths = append(o.ignorePaths, parsePath(name))
+		}
+	}
+}
+
+// IgnoreUnknown skips the comparison of unknown fields.
+func IgnoreUnknown() Option {
+	return func(o *options) {
+		o.ignoreUnknown = true
+	}
+}
+
+// UnorderedUnknown compares unknown fields as a multiset per field number, so
+// that only their contents matter and not the order they appear in on the wire.
+func UnorderedUnknown() Option {
+	return func(o *options) {
+		o.unorderedUnknown = true
+	}
+}
+
+// FloatAbsTolerance accepts float and double values that differ by at most
+// epsilon. Without paths the tolerance applies to every field.
+func FloatAbsTolerance(epsilon float64, paths ...string) Option {
+	return floatToleranceOption(absTolerance, epsilon, paths)
+}
+
+// FloatRelTolerance accepts float and double values whose difference relative
+// to the larger magnitude is at most epsilon. Without paths the tolerance
+// applies to every field.
+func FloatRelTolerance(epsilon float64, paths ...string) Option {
+	return floatToleranceOption(relTolerance, epsilon, paths)
+}
+
+// FloatULPTolerance accepts float and double values that are at most ulps
+// representable values apart. Without paths the tolerance applies to every
+// field.
+func FloatULPTolerance(ulps uint64, paths ...string) Option {
+	return floatToleranceOption(ulpTolerance, float64(ulps), paths)
+}
+
+func floatToleranceOption(kind toleranceKind, value float64, paths []string) Option {
+	return func(o *options) {
+		t := floatTolerance{kind: kind, value: value}
+		for _, p := range paths {
+			t.paths = append(t.paths, parsePath(p))
+		}
+		o.tolerances = append(o.tolerances, t)
+	}
+}
+
+// TimeTolerance accepts google.protobuf.Timestamp and google.protobuf.Duration
+// values that differ by at most d. Without paths the tolerance applies to every
+// field.
+func TimeTolerance(d time.Duration, paths ...string) Option {
+	return func(o *options) {
+		t := timeTolerance{value: d}
+		for _, p := range paths {
+			t.paths = append(t.paths, parsePath(p))
+		}
+		o.timeTolerances = append(o.timeTolerances, t)
+	}
+}
+
+// UnorderedLists compares the repeated fields at the given paths as
+// multisets, ignoring the order of their elements. Without paths every
+// repeated field is compared this way.
+func UnorderedLists(paths ...string) Option {
+	return func(o *options) {
+		if len(paths) == 0 {
+			o.unorderedAll = true
+		}
+		for _, p := range paths {
+			o.unorderedPaths = append(o.unorderedPaths, parsePath(p))
+		}
+	}
+}
+
+// ListKey compares the elements of the repeated message field at path by
+// pairing the elements that share the same value for the key field, no matter
+// where they sit in the list. Unpaired elements are reported as missing or
+// unexpected, e.g. "repeated_type.[id=2]: missing element", and nil elements
+// are paired under the key <nil>. A key that does not name a singular field of
+// the elements is reported as a difference of the list.
+func ListKey(path, key string) Option {
+	return func(o *options) {
+		o.listKeys = append(o.listKeys, listKey{path: parsePath(path), key: protoreflect.Name(key)})
+	}
+}
+
+// AnyResolver sets the resolver used to find the types of the messages
+// embedded in google.protobuf.Any fields. Defaults to protoregistry.GlobalTypes.
+func AnyResolver(r protoregistry.MessageTypeResolver) Option {
+	return func(o *options) {
+		o.resolver = r
+	}
+}
+
+// FormatBytes sets how mismatching bytes values are rendered in diffs.
+// Defaults to BytesHexDump.
+func FormatBytes(f BytesFormat) Option {
+	return func(o *options) {
+		o.bytesFormat = f
+	}
+}
+
+// EquateEmpty treats nil and empty messages, empty repeated fields and empty
+// maps as equal at any depth.
+func EquateEmpty() Option {
+	return func(o *options) {
+		o.equateEmpty = true
+	}
+}
+
+// EquateWrapperZero treats an unset wrapper field, such as a
+// google.protobuf.StringValue, as equal to a wrapper holding the zero value.
+func EquateWrapperZero() Option {
+	return func(o *options) {
+		o.equateWrapperZero = true
+	}
+}
+
+// Partial treats the expected message as a template, checking only the fields
+// that are set in it. Fields, list elements and map entries that are only
+// present in the actual message are ignored at any depth. A shorter expected
+// list is aligned with the actual list like lists of different lengths, so its
+// elements need not sit at the same indexes, e.g. [10] matches [9 10 11].
+func Partial() Option {
+	return func(o *options) {
+		o.partial = true
+	}
+}
+
+// Comparer compares the messages of the given full name, e.g. "acme.Money",
+// with the function f instead of field by field. The function is only called
+// when both messages are set.
+func Comparer(name string, f func(x, y protoreflect.Message) bool) Option {
+	return func(o *options) {
+		if o.comparers == nil {
+			o.comparers = make(map[protoreflect.FullName]func(x, y protoreflect.Message) bool)
+		}
+		o.comparers[protoreflect.FullName(name)] = f
+	}
+}
+
+// TransformPaths applies the Transformer to both sides of the scalar fields at
+// the given paths before comparing them, e.g. TransformPaths(LowerCase, "str_val").
+// For repeated and map fields the Transformer is applied to every element.
+func TransformPaths(t Transformer, paths ...string) Option {
+	return func(o *options) {
+		tr := transformer{t: t}
+		for _, p := range paths {
+			tr.paths = append(tr.paths, parsePath(p))
+		}
+		o.transforms = append(o.transforms, tr)
+	}
+}
+
+// TransformKinds applies the Transformer to both sides of every scalar value of
+// the given kinds before comparing them, e.g. TransformKinds(RoundFloat(2), protoreflect.DoubleKind).
+func TransformKinds(t Transformer, kinds ...protoreflect.Kind) Option {
+	return func(o *options) {
+		o.transforms = append(o.transforms, transformer{t: t, kinds: kinds})
+	}
+}
+
+// SortRepeated sorts the elements of the repeated scalar fields at the given
+// paths before comparing them by position. Reported indexes refer to the
+// sorted lists.
+func SortRepeated(paths ...string) Option {
+	return func(o *options) {
+		for _, p := range paths {
+			o.sortedPaths = append(o.sortedPaths, parsePath(p))
+		}
+	}
+}
+
+// ignored reports whether the field at the given path is skipped.
+func (o *options) ignored(fd protoreflect.FieldDescriptor, p fieldPath) bool {
+	return o.ignoreNames[fd.FullName()] || o.ignoredPath(p) || o.maskedOut(p)
+}
+
+// ignoredPath reports whether the value at the given path is skipped.
+func (o *options) ignoredPath(p fieldPath) bool {
+	return matchesAny(p, o.ignorePaths)
+}
+
+// equateMissing reports whether a field set to v on one side only is treated
+// as equal to the unset field on the other side.
+func (o *options) equateMissing(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+	if o.equateEmpty && isEmptyValue(fd, v) {
+		return true
+	}
+	return o.equateWrapperZero && !fd.IsList() && !fd.IsMap() && fd.Message() != nil &&
+		isWrapperMessage(fd.Message()) && isZeroWrapper(v.Message())
+}
+
+// floatTolerances returns the tolerances for the value at the given path.
+// Tolerances restricted to the path take precedence over global ones.
+func (o *options) floatTolerances(p fieldPath) []floatTolerance {
+	var global, matched []floatTolerance
+	for _, t := range o.tolerances {
+		switch {
+		case len(t.paths) == 0:
+			global = append(global, t)
+		case t.appliesTo(p):
+			matched = append(matched, t)
+		}
+	}
+	if len(matched) > 0 {
+		return matched
+	}
+	return global
+}
+
+// timeTolerance returns the tolerance for the timestamp or duration at the
+// given path. Tolerances restricted to the path take precedence over global
+// ones.
+func (o *options) timeTolerance(p fieldPath) (time.Duration, bool) {
+	var global *timeTolerance
+	for i, t := range o.timeTolerances {
+		switch {
+		case len(t.paths) == 0:
+			global = &o.timeTolerances[i]
+		case t.appliesTo(p):
+			return t.value, true
+		}
+	}
+	if global != nil {
+		return global.value, true
+	}
+	return 0, false
+}
+
+// unorderedList reports whether the list at the given path is compared as a
+// multiset.
+func (o *options) unorderedList(p fieldPath) bool {
+	if o.unorderedAll {
+		return true
+	}
+	return matchesAny(p, o.unorderedPaths)
+}
+
+// listKey returns the key field used to pair the elements of the list at the
+// given path, or nil if the elements are compared by position. It returns an
+// error if the ListKey option for the path does not name a singular field of
+// the elements.
+func (o *options) listKey(p fieldPath, fd protoreflect.FieldDescriptor) (protoreflect.FieldDescriptor, error) {
+	for _, lk := range o.listKeys {
+		if !p.matches(lk.path) {
+			continue
+		}
+		if fd.Message() == nil {
+			return nil, fmt.Errorf("invalid list key %q: field %q is not a message", lk.key, fd.Name())
+		}
+		kd := fd.Message().Fields().ByName(lk.key)
+		switch {
+		case kd == nil:
+			return nil, fmt.Errorf("invalid list key %q: %s has no field %q", lk.key, fd.Message().FullName(), lk.key)
+		case kd.IsList() || kd.IsMap():
+			return nil, fmt.Errorf("invalid list key %q: field %q is repeated", lk.key, lk.key)
+		}
+		return kd, nil
+	}
+	return nil, nil
+}
+
+// anyResolver returns the resolver for the types embedded in Any messages.
+func (o *options) anyResolver() protoregistry.MessageTypeResolver {
+	if o.resolver != nil {
+		return o.resolver
+	}
+	return protoregistry.GlobalTypes
+}
+
+// transformers returns the transformers for the scalar value of the given
+// field at the given path, in the order they were configured.
+func (o *options) transformers(p fieldPath, fd protoreflect.FieldDescriptor) []Transformer {
+	var ts []Transformer
+	for _, t := range o.transforms {
+		if t.appliesTo(p, fd) {
+			ts = append(ts, t.t)
+		}
+	}
+	return ts
+}
+
+// sortedList reports whether the list at the given path is sorted before it is
+// compared.
+func (o *options) sortedList(p fieldPath) bool {
+	return matchesAny(p, o.sortedPaths)
+}
@@ -0,0 +1,568 @@
+package protocmp
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/nbaztec/protocmp/protos/sample"
+)
+
+func TestIgnoreFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  *sample.Outer
+		ignore []string
+		diff   *DiffError
+	}{
+		{
+			name: "full name",
+			input: makeInput(func(v *sample.Outer) {
+				v.TimestampType = nil
+			}),
+			ignore: []string{"sample.Outer.timestamp_type"},
+		},
+		{
+			name: "path",
+			input: makeInput(func(v *sample.Outer) {
+				v.NestedMessage.Inner.Id = "foo"
+			}),
+			ignore: []string{"nested_message.inner.id"},
+		},
+		{
+			name: "list wildcard",
+			input: makeInput(func(v *sample.Outer) {
+				v.RepeatedType[0].Id = "foo"
+				v.RepeatedType[1].Id = "bar"
+			}),
+			ignore: []string{"repeated_type[*].id"},
+		},
+		{
+			name: "map key",
+			input: makeInput(func(v *sample.Outer) {
+				v.MapType["A"].Id = "foo"
+			}),
+			ignore: []string{"map_type[A]"},
+		},
+		{
+			name: "other fields are compared",
+			input: makeInput(func(v *sample.Outer) {
+				v.MapType["A"].Id = "foo"
+				v.MapType["B"].Id = "bar"
+			}),
+			ignore: []string{"map_type[A]"},
+			diff: &DiffError{
+				Field:    "map_type.[B].id",
+				Message:  "value mismatch",
+				Expected: `"BB"`,
+				Actual:   `"bar"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			check(
+				t,
+				makeInput(nil),
+				tt.input,
+				tt.diff,
+				IgnoreFields(tt.ignore...),
+			)
+		})
+	}
+}
+
+func TestIgnoreUnknown(t *testing.T) {
+	input := makeInput(nil)
+	input.ProtoReflect().SetUnknown(appendUnknownVarint(nil, 17, 42))
+
+	check(t, makeInput(nil), input, nil, IgnoreUnknown())
+}
+
+func TestUnorderedUnknown(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected []byte
+		actual   []byte
+		diff     bool
+	}{
+		{
+			name:     "same order",
+			expected: appendUnknownVarint(appendUnknownVarint(nil, 17, 42), 18, 1),
+			actual:   appendUnknownVarint(appendUnknownVarint(nil, 17, 42), 18, 1),
+		},
+		{
+			name:     "different order",
+			expected: appendUnknownVarint(appendUnknownVarint(appendUnknownVarint(nil, 17, 42), 18, 1), 17, 43),
+			actual:   appendUnknownVarint(appendUnknownVarint(appendUnknownVarint(nil, 18, 1), 17, 43), 17, 42),
+		},
+		{
+			name:     "different value",
+			expected: appendUnknownVarint(appendUnknownVarint(nil, 17, 42), 18, 1),
+			actual:   appendUnknownVarint(appendUnknownVarint(nil, 18, 1), 17, 43),
+			diff:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			expected := makeInput(nil)
+			expected.ProtoReflect().SetUnknown(tt.expected)
+			actual := makeInput(nil)
+			actual.ProtoReflect().SetUnknown(tt.actual)
+
+			err := Equal(expected, actual, UnorderedUnknown())
+			if tt.diff != (err != nil) {
+				t.Errorf("mismatch: want diff %v, got %v", tt.diff, err)
+			}
+		})
+	}
+}
+
+func TestUnorderedLists(t *testing.T) {
+	tests := []struct {
+		name  string
+		input *sample.Outer
+		opt   Option
+		diffs DiffErrors
+	}{
+		{
+			name: "all lists",
+			input: makeInput(func(v *sample.Outer) {
+				v.RepeatedType = []*sample.Outer_Inner{nil, {Id: "2"}, {Id: "1"}}
+				v.RepeatedTypeSimple = []int32{11, 9, 10}
+			}),
+			opt: UnorderedLists(),
+		},
+		{
+			name: "selected list",
+			input: makeInput(func(v *sample.Outer) {
+				v.RepeatedType = []*sample.Outer_Inner{nil, {Id: "2"}, {Id: "1"}}
+				v.RepeatedTypeSimple = []int32{11, 9, 10}
+			}),
+			opt: UnorderedLists("repeated_type_simple"),
+			diffs: DiffErrors{
+				{
+					Field:    "repeated_type.[0]",
+					Message:  "value mismatch",
+					Expected: `<id:"1">`,
+					Actual:   `<nil>`,
+				},
+				{
+					Field:    "repeated_type.[2]",
+					Message:  "value mismatch",
+					Expected: `<nil>`,
+					Actual:   `<id:"1">`,
+				},
+			},
+		},
+		{
+			name: "unmatched elements",
+			input: makeInput(func(v *sample.Outer) {
+				v.RepeatedType = []*sample.Outer_Inner{{Id: "2"}, {Id: "3"}, nil, {Id: "4"}}
+			}),
+			opt: UnorderedLists(),
+			diffs: DiffErrors{
+				{
+					Field:    "repeated_type.[0]",
+					Message:  "missing element",
+					Expected: `<id:"1">`,
+					Actual:   `<nil>`,
+				},
+				{
+					Field:    "repeated_type.[1]",
+					Message:  "unexpected element",
+					Expected: `<nil>`,
+					Actual:   `<id:"3">`,
+				},
+				{
+					Field:    "repeated_type.[3]",
+					Message:  "unexpected element",
+					Expected: `<nil>`,
+					Actual:   `<id:"4">`,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual := withoutPaths(tt.diffs, EqualAll(makeInput(nil), tt.input, tt.opt))
+			if !reflect.DeepEqual(tt.diffs, actual) {
+				t.Errorf("mismatch err\n++ want:\n%s\n-- got:\n%s", tt.diffs, actual)
+			}
+		})
+	}
+}
+
+func TestUnorderedListsTolerance(t *testing.T) {
+	// within compares the ids of two elements as numbers within 0.15 of each
+	// other, which pairs 1.1 with both 1.0 and 1.2.
+	within := Comparer("sample.Outer.Inner", func(x, y protoreflect.Message) bool {
+		id := x.Descriptor().Fields().ByName("id")
+		a, _ := strconv.ParseFloat(x.Get(id).String(), 64)
+		b, _ := strconv.ParseFloat(y.Get(id).String(), 64)
+		return math.Abs(a-b) <= 0.15
+	})
+	list := func(ids ...string) *sample.Outer {
+		v := &sample.Outer{}
+		for _, id := range ids {
+			v.RepeatedType = append(v.RepeatedType, &sample.Outer_Inner{Id: id})
+		}
+		return v
+	}
+
+	if d := EqualAll(list("1.1", "1.0"), list("1.0", "1.2"), UnorderedLists(), within); d != nil {
+		t.Errorf("expected the elements to be paired, got %v", d)
+	}
+	if d := EqualAll(list("1.1", "1.0"), list("1.0", "1.5"), UnorderedLists(), within); len(d) != 2 {
+		t.Errorf("expected a missing and an unexpected element, got %v", d)
+	}
+}
+
+func TestListKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		input *sample.Outer
+		opt   Option
+		diffs DiffErrors
+	}{
+		{
+			name: "reordered",
+			input: makeInput(func(v *sample.Outer) {
+				v.RepeatedType = []*sample.Outer_Inner{{Id: "2"}, nil, {Id: "1"}}
+			}),
+		},
+		{
+			name: "missing and unexpected keys",
+			input: makeInput(func(v *sample.Outer) {
+				v.RepeatedType = []*sample.Outer_Inner{{Id: "3"}, nil, {Id: "1"}}
+			}),
+			diffs: DiffErrors{
+				{
+					Field:    "repeated_type.[id=2]",
+					Message:  "missing element",
+					Expected: `<id:"2">`,
+					Actual:   `<nil>`,
+				},
+				{
+					Field:    "repeated_type.[id=3]",
+					Message:  "unexpected element",
+					Expected: `<nil>`,
+					Actual:   `<id:"3">`,
+				},
+			},
+		},
+		{
+			name: "different length",
+			input: makeInput(func(v *sample.Outer) {
+				v.RepeatedType = []*sample.Outer_Inner{{Id: "1"}, nil}
+			}),
+			diffs: DiffErrors{
+				{
+					Field:    "repeated_type.[id=2]",
+					Message:  "missing element",
+					Expected: `<id:"2">`,
+					Actual:   `<nil>`,
+				},
+			},
+		},
+		{
+			name: "nil and empty keys",
+			input: makeInput(func(v *sample.Outer) {
+				v.RepeatedType = []*sample.Outer_Inner{{Id: "1"}, {Id: "2"}, {}}
+			}),
+			diffs: DiffErrors{
+				{
+					Field:    "repeated_type.[id=<nil>]",
+					Message:  "missing element",
+					Expected: `<nil>`,
+					Actual:   `<nil>`,
+				},
+				{
+					Field:    "repeated_type.[id=]",
+					Message:  "unexpected element",
+					Expected: `<nil>`,
+					Actual:   `<>`,
+				},
+			},
+		},
+		{
+			name:  "unknown key",
+			input: makeInput(nil),
+			opt:   ListKey("repeated_type", "name"),
+			diffs: DiffErrors{
+				{
+					Field:    "repeated_type",
+					Message:  `invalid list key "name": sample.Outer.Inner has no field "name"`,
+					Expected: `[<id:"1"> <id:"2"> <nil>]`,
+					Actual:   `[<id:"1"> <id:"2"> <nil>]`,
+				},
+			},
+		},
+		{
+			name:  "scalar list",
+			input: makeInput(nil),
+			opt:   ListKey("repeated_type_simple", "id"),
+			diffs: DiffErrors{
+				{
+					Field:    "repeated_type_simple",
+					Message:  `invalid list key "id": field "repeated_type_simple" is not a message`,
+					Expected: `[9 10 11]`,
+					Actual:   `[9 10 11]`,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			opt := tt.opt
+			if opt == nil {
+				opt = ListKey("repeated_type", "id")
+			}
+			actual := withoutPaths(tt.diffs, EqualAll(makeInput(nil), tt.input, opt))
+			if !reflect.DeepEqual(tt.diffs, actual) {
+				t.Errorf("mismatch err\n++ want:\n%s\n-- got:\n%s", tt.diffs, actual)
+			}
+		})
+	}
+}
+
+func TestEquateEmpty(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected *sample.Outer
+		input    *sample.Outer
+	}{
+		{
+			name:     "nil message",
+			expected: nil,
+			input:    &sample.Outer{},
+		},
+		{
+			name: "unset field",
+			expected: makeInput(func(v *sample.Outer) {
+				v.NestedMessage = nil
+			}),
+			input: makeInput(func(v *sample.Outer) {
+				v.NestedMessage = &sample.Outer_NestedInner{Inner: &sample.Outer_NestedInner_Inner{}}
+			}),
+		},
+		{
+			name:     "list element",
+			expected: makeInput(nil),
+			input: makeInput(func(v *sample.Outer) {
+				v.RepeatedType[2] = &sample.Outer_Inner{}
+			}),
+		},
+		{
+			name:     "map value",
+			expected: makeInput(nil),
+			input: makeInput(func(v *sample.Outer) {
+				v.MapType["C"] = &sample.Outer_Inner{}
+			}),
+		},
+		{
+			name: "nested empty message",
+			expected: makeInput(func(v *sample.Outer) {
+				v.NestedMessage = &sample.Outer_NestedInner{}
+			}),
+			input: makeInput(func(v *sample.Outer) {
+				v.NestedMessage = &sample.Outer_NestedInner{Inner: &sample.Outer_NestedInner_Inner{}}
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			check(t, tt.expected, tt.input, nil, EquateEmpty())
+
+			if err := Equal(tt.expected, tt.input); err == nil {
+				t.Error("expected a difference without EquateEmpty")
+			}
+		})
+	}
+
+	check(
+		t,
+		makeInput(func(v *sample.Outer) {
+			v.NestedMessage = nil
+		}),
+		makeInput(func(v *sample.Outer) {
+			v.NestedMessage = &sample.Outer_NestedInner{Inner: &sample.Outer_NestedInner_Inner{Id: "1"}}
+		}),
+		&DiffError{
+			Field:    "nested_message",
+			Message:  "value mismatch",
+			Expected: `<nil>`,
+			Actual:   `<inner:<id:"1">>`,
+		},
+		EquateEmpty(),
+	)
+}
+
+func TestPartial(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected *sample.Outer
+		input    *sample.Outer
+		diff     *DiffError
+	}{
+		{
+			name:     "nil template",
+			expected: nil,
+			input:    makeInput(nil),
+		},
+		{
+			name:     "field subset",
+			expected: &sample.Outer{StrVal: "foo", IntVal: 1},
+			input:    makeInput(nil),
+		},
+		{
+			name:     "nested message",
+			expected: &sample.Outer{NestedMessage: &sample.Outer_NestedInner{Inner: &sample.Outer_NestedInner_Inner{Id: "x"}}},
+			input:    makeInput(nil),
+			diff: &DiffError{
+				Field:    "nested_message.inner.id",
+				Message:  "value mismatch",
+				Expected: `"x"`,
+				Actual:   `"123"`,
+			},
+		},
+		{
+			name:     "list prefix",
+			expected: &sample.Outer{RepeatedType: []*sample.Outer_Inner{{}}, RepeatedTypeSimple: []int32{9, 10}},
+			input:    makeInput(nil),
+		},
+		{
+			name:     "list subsequence",
+			expected: &sample.Outer{RepeatedType: []*sample.Outer_Inner{{Id: "2"}}, RepeatedTypeSimple: []int32{10}},
+			input:    makeInput(nil),
+		},
+		{
+			name:     "list element mismatch",
+			expected: &sample.Outer{RepeatedTypeSimple: []int32{10, 12}},
+			input:    makeInput(nil),
+			diff: &DiffError{
+				Field:    "repeated_type_simple.[1]",
+				Message:  "value mismatch",
+				Expected: `12`,
+				Actual:   `11`,
+			},
+		},
+		{
+			name:     "list too short",
+			expected: &sample.Outer{RepeatedTypeSimple: []int32{9, 10, 11, 12}},
+			input:    makeInput(nil),
+			diff: &DiffError{
+				Field:    "repeated_type_simple.[3]",
+				Message:  "missing element",
+				Expected: `12`,
+				Actual:   `<nil>`,
+			},
+		},
+		{
+			name:     "map subset",
+			expected: &sample.Outer{MapType: map[string]*sample.Outer_Inner{"B": {}}, MapTypeSimple: map[string]int32{"B": 30}},
+			input:    makeInput(nil),
+		},
+		{
+			name:     "map value",
+			expected: &sample.Outer{MapTypeSimple: map[string]int32{"B": 31}},
+			input:    makeInput(nil),
+			diff: &DiffError{
+				Field:    "map_type_simple.[B]",
+				Message:  "value mismatch",
+				Expected: `31`,
+				Actual:   `30`,
+			},
+		},
+		{
+			name:     "missing field",
+			expected: &sample.Outer{StrVal: "foo"},
+			input: makeInput(func(v *sample.Outer) {
+				v.StrVal = ""
+			}),
+			diff: &DiffError{
+				Field:    "str_val",
+				Message:  "value mismatch",
+				Expected: `"foo"`,
+				Actual:   `""`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual := withoutPath(tt.diff, Equal(tt.expected, tt.input, Partial()))
+			if !reflect.DeepEqual(tt.diff, actual) {
+				t.Errorf("mismatch err\n++ want:\n%s\n-- got:\n%s", tt.diff, actual)
+			}
+		})
+	}
+}
+
+func TestComparer(t *testing.T) {
+	caseInsensitive := Comparer("sample.Outer.Inner", func(x, y protoreflect.Message) bool {
+		id := x.Descriptor().Fields().ByName("id")
+		return strings.EqualFold(x.Get(id).String(), y.Get(id).String())
+	})
+
+	check(
+		t,
+		makeInput(nil),
+		makeInput(func(v *sample.Outer) {
+			v.MapType["A"].Id = "aa"
+		}),
+		nil,
+		caseInsensitive,
+	)
+
+	check(
+		t,
+		makeInput(nil),
+		makeInput(func(v *sample.Outer) {
+			v.MapType["A"].Id = "AB"
+		}),
+		&DiffError{
+			Field:    "map_type.[A]",
+			Message:  "value mismatch",
+			Expected: `<id:"AA">`,
+			Actual:   `<id:"AB">`,
+		},
+		caseInsensitive,
+	)
+
+	check(
+		t,
+		makeInput(nil),
+		makeInput(func(v *sample.Outer) {
+			v.MapType["A"] = nil
+		}),
+		&DiffError{
+			Field:    "map_type.[A]",
+			Message:  "value mismatch",
+			Expected: `<id:"AA">`,
+			Actual:   `<nil>`,
+		},
+		caseInsensitive,
+	)
+}
+
+func appendUnknownVarint(b []byte, num protowire.Number, v uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
@@ -0,0 +1,373 @@
+package protocmp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// fieldPath is the location of a value relative to the root message. Each step
+// is either a field name, a bracketed list index or map key such as "[0]", or
+// the parenthesized type of a message embedded in an Any.
+type fieldPath []string
+
+var closing = map[byte]byte{'[': ']', '(': ')'}
+
+// parsePath parses a dot separated path such as "repeated_type[*].id",
+// "map_type.[A]" or "any_type.(sample.Outer.Inner).id". The wildcard "[*]"
+// matches any list index or map key. Map keys may also be quoted as rendered
+// by Path.String, e.g. `map_type["A"]`.
+func parsePath(s string) fieldPath {
+	var p fieldPath
+	for len(s) > 0 {
+		switch s[0] {
+		case '.':
+			s = s[1:]
+		case '[', '(':
+			var step string
+			step, s = parseBracketed(s)
+			p = append(p, step)
+		default:
+			n := strings.IndexAny(s, ".[(")
+			if n < 0 {
+				n = len(s)
+			}
+			p = append(p, s[:n])
+			s = s[n:]
+		}
+	}
+	return p
+}
+
+// parseBracketed splits the bracketed or parenthesized step at the start of s
+// from the rest of the path. A quoted map key is unquoted, so that `["a.b"]`
+// reads as "[a.b]".
+func parseBracketed(s string) (string, string) {
+	if strings.HasPrefix(s, `["`) {
+	scan:
+		for i := 2; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				i++
+			case '"':
+				key, err := strconv.Unquote(s[1 : i+1])
+				if err != nil || !strings.HasPrefix(s[i+1:], "]") {
+					break scan
+				}
+				return "[" + key + "]", s[i+2:]
+			}
+		}
+	}
+
+	n := strings.IndexByte(s, closing[s[0]])
+	if n < 0 {
+		n = len(s) - 1
+	}
+	return s[:n+1], s[n+1:]
+}
+
+// push returns a copy of the path with the step appended.
+func (p fieldPath) push(step string) fieldPath {
+	np := make(fieldPath, len(p), len(p)+1)
+	copy(np, p)
+	return append(np, step)
+}
+
+// field returns the path without any trailing list index or map key steps.
+func (p fieldPath) field() fieldPath {
+	n := len(p)
+	for n > 0 && strings.HasPrefix(p[n-1], "[") {
+		n--
+	}
+	return p[:n]
+}
+
+// names returns the path with only its field name steps.
+func (p fieldPath) names() fieldPath {
+	var np fieldPath
+	for _, step := range p {
+		if !strings.HasPrefix(step, "[") && !strings.HasPrefix(step, "(") {
+			np = append(np, step)
+		}
+	}
+	return np
+}
+
+// hasPrefix reports whether the path begins with the steps of prefix.
+func (p fieldPath) hasPrefix(prefix fieldPath) bool {
+	if len(prefix) > len(p) {
+		return false
+	}
+	for i, step := range prefix {
+		if p[i] != step {
+			return false
+		}
+	}
+	return true
+}
+
+// matches reports whether the path matches the pattern.
+func (p fieldPath) matches(pattern fieldPath) bool {
+	if len(p) != len(pattern) {
+		return false
+	}
+	for i, step := range pattern {
+		if step == "[*]" && strings.HasPrefix(p[i], "[") {
+			continue
+		}
+		if step != p[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesAny reports whether the path matches any of the patterns.
+func matchesAny(p fieldPath, patterns []fieldPath) bool {
+	for _, pattern := range patterns {
+		if p.matches(pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p fieldPath) String() string {
+	return strings.Join(p, ".")
+}
+
+// StepKind is the kind of a PathStep.
+type StepKind int
+
+const (
+	// FieldStep is a field of a message, given by PathStep.Field.
+	FieldStep StepKind = iota
+	// IndexStep is an element of a repeated field or of a JSON array in a
+	// google.protobuf.Struct, given by PathStep.Index.
+	IndexStep
+	// MapKeyStep is an entry of a map field, given by PathStep.Key.
+	MapKeyStep
+	// AnyStep is the message embedded in a google.protobuf.Any, whose type is
+	// given by PathStep.Type.
+	AnyStep
+	// OneofStep is a oneof of a message, given by PathStep.Oneof, whose set
+	// field differs on both sides. PathStep.Field is the field set in the
+	// expected message.
+	OneofStep
+	// ListKeyStep is an element of a repeated message field paired by ListKey,
+	// given by its key field PathStep.Field and key value PathStep.Key. The key
+	// value of a nil element is invalid and is rendered as <nil>.
+	ListKeyStep
+	// UnknownFieldStep is an unknown field of a message, given by
+	// PathStep.Number.
+	UnknownFieldStep
+	// JSONKeyStep is a key of a JSON object in a google.protobuf.Struct, given
+	// by PathStep.Key.
+	JSONKeyStep
+)
+
+// PathStep is a single step of a Path. Only the fields for its Kind are set.
+type PathStep struct {
+	Kind   StepKind
+	Field  protoreflect.FieldDescriptor
+	Index  int
+	Key    protoreflect.Value
+	Type   protoreflect.FullName
+	Oneof  protoreflect.OneofDescriptor
+	Number protoreflect.FieldNumber
+}
+
+func fieldStep(fd protoreflect.FieldDescriptor) PathStep {
+	return PathStep{Kind: FieldStep, Field: fd}
+}
+
+func indexStep(i int) PathStep {
+	return PathStep{Kind: IndexStep, Index: i}
+}
+
+func mapKeyStep(k protoreflect.MapKey) PathStep {
+	return PathStep{Kind: MapKeyStep, Key: k.Value()}
+}
+
+func anyStep(name protoreflect.FullName) PathStep {
+	return PathStep{Kind: AnyStep, Type: name}
+}
+
+func oneofStep(od protoreflect.OneofDescriptor, fd protoreflect.FieldDescriptor) PathStep {
+	return PathStep{Kind: OneofStep, Oneof: od, Field: fd}
+}
+
+func listKeyStep(kd protoreflect.FieldDescriptor, v protoreflect.Value) PathStep {
+	return PathStep{Kind: ListKeyStep, Field: kd, Key: v}
+}
+
+func unknownFieldStep(num protoreflect.FieldNumber) PathStep {
+	return PathStep{Kind: UnknownFieldStep, Number: num}
+}
+
+func jsonKeyStep(k string) PathStep {
+	return PathStep{Kind: JSONKeyStep, Key: protoreflect.ValueOfString(k)}
+}
+
+// key returns the step as rendered in DiffError.Field.
+func (s PathStep) key() string {
+	switch s.Kind {
+	case FieldStep:
+		return string(s.Field.Name())
+	case IndexStep:
+		return fmt.Sprintf("[%d]", s.Index)
+	case MapKeyStep:
+		return fmt.Sprintf("[%s]", s.Key.String())
+	case AnyStep:
+		return fmt.Sprintf("(%s)", s.Type)
+	case OneofStep:
+		return string(s.Oneof.Name())
+	case ListKeyStep:
+		return fmt.Sprintf("[%s=%s]", s.Field.Name(), s.listKey())
+	case UnknownFieldStep:
+		if s.Number == 0 {
+			return "#?"
+		}
+		return fmt.Sprintf("#%d", s.Number)
+	default:
+		return s.Key.String()
+	}
+}
+
+// listKey renders the key value of a ListKeyStep, or <nil> for a nil element.
+func (s PathStep) listKey() string {
+	if !s.Key.IsValid() {
+		return "<nil>"
+	}
+	return fmtKey(s.Key, s.Field)
+}
+
+// Path is the location of a difference as a sequence of steps from the root
+// message. The path of a difference of the root message itself is empty.
+type Path []PathStep
+
+// String renders the path in protobuf style with field names, e.g.
+// `repeated_type[1].id`, `map_type["A"]` or `any_type.(sample.Outer.Inner).id`.
+// Keys of JSON objects read like field names, e.g. `struct_type.user.name`,
+// unless they are not plain names, e.g. `struct_type["a.b"]`. Paths made of
+// field, index, map key, JSON key and Any steps can be passed back to
+// IgnoreFields and the other options that take paths.
+func (p Path) String() string {
+	return p.render(false)
+}
+
+// JSONString renders the path like String, but with the JSON names of fields,
+// e.g. `repeatedType[1].id`.
+func (p Path) JSONString() string {
+	return p.render(true)
+}
+
+func (p Path) render(jsonNames bool) string {
+	var b strings.Builder
+	for _, s := range p {
+		switch s.Kind {
+		case FieldStep:
+			if s.Field.IsExtension() {
+				fmt.Fprintf(&b, "[%s]", s.Field.FullName())
+				continue
+			}
+			if b.Len() > 0 {
+				b.WriteByte('.')
+			}
+			if jsonNames {
+				b.WriteString(s.Field.JSONName())
+			} else {
+				b.WriteString(string(s.Field.Name()))
+			}
+		case IndexStep:
+			fmt.Fprintf(&b, "[%d]", s.Index)
+		case MapKeyStep:
+			fmt.Fprintf(&b, "[%s]", fmtPathKey(s.Key))
+		case JSONKeyStep:
+			if k := s.Key.String(); isPlainJSONKey(k) {
+				b.WriteByte('.')
+				b.WriteString(k)
+			} else {
+				fmt.Fprintf(&b, "[%s]", fmtPathKey(s.Key))
+			}
+		case ListKeyStep:
+			name := string(s.Field.Name())
+			if jsonNames {
+				name = s.Field.JSONName()
+			}
+			key := s.listKey()
+			if s.Key.IsValid() && s.Field.Kind() == protoreflect.StringKind {
+				key = strconv.Quote(key)
+			}
+			fmt.Fprintf(&b, "[%s=%s]", name, key)
+		default:
+			if b.Len() > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(s.key())
+		}
+	}
+	return b.String()
+}
+
+// JSONPointer renders the path as an RFC 6901 JSON Pointer into the JSON form
+// of the message, with the JSON names of fields, e.g. `/repeatedType/1/id`.
+// Any steps are left out, as the fields of the embedded message are inlined in
+// JSON, and a oneof is rendered as the field set in the expected message. An
+// element paired by ListKey is rendered as its key, e.g. `/repeatedType/id=2`,
+// since it may sit at different indexes on both sides; such a pointer does not
+// resolve against the JSON document.
+func (p Path) JSONPointer() string {
+	var b strings.Builder
+	for _, s := range p {
+		var token string
+		switch s.Kind {
+		case FieldStep:
+			token = s.Field.JSONName()
+			if s.Field.IsExtension() {
+				token = fmt.Sprintf("[%s]", s.Field.FullName())
+			}
+		case IndexStep:
+			token = strconv.Itoa(s.Index)
+		case MapKeyStep, JSONKeyStep:
+			token = s.Key.String()
+		case ListKeyStep:
+			token = fmt.Sprintf("%s=%s", s.Field.JSONName(), s.listKey())
+		case OneofStep:
+			token = s.Field.JSONName()
+		case AnyStep:
+			continue
+		default:
+			token = s.key()
+		}
+		b.WriteByte('/')
+		b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(token))
+	}
+	return b.String()
+}
+
+// isPlainJSONKey reports whether a JSON object key reads as a single name in
+// a path, i.e. if it is not empty and holds no '.', '[' or '('.
+func isPlainJSONKey(k string) bool {
+	return k != "" && !strings.ContainsAny(k, ".[(")
+}
+
+// jsonKeyPathStep returns the step of a JSON object key in a fieldPath: the
+// key itself if it is plain, else the key bracketed like a map key, so that
+// both forms rendered by Path.String parse back to it.
+func jsonKeyPathStep(k string) string {
+	if isPlainJSONKey(k) {
+		return k
+	}
+	return "[" + k + "]"
+}
+
+// fmtPathKey renders a map key in a path, quoting strings.
+func fmtPathKey(v protoreflect.Value) string {
+	if s, ok := v.Interface().(string); ok {
+		return strconv.Quote(s)
+	}
+	return v.String()
+}
@@ -0,0 +1,280 @@
+package protocmp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/nbaztec/protocmp/protos/sample"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected fieldPath
+	}{
+		{input: "str_val", expected: fieldPath{"str_val"}},
+		{input: "nested_message.inner.id", expected: fieldPath{"nested_message", "inner", "id"}},
+		{input: "repeated_type[*].id", expected: fieldPath{"repeated_type", "[*]", "id"}},
+		{input: "repeated_type.[1].id", expected: fieldPath{"repeated_type", "[1]", "id"}},
+		{input: "map_type[A]", expected: fieldPath{"map_type", "[A]"}},
+		{input: `map_type["a.b/c"]`, expected: fieldPath{"map_type", "[a.b/c]"}},
+		{input: `map_type["\"]"].id`, expected: fieldPath{"map_type", `["]]`, "id"}},
+		{input: "any_type.(sample.Outer.Inner).id", expected: fieldPath{"any_type", "(sample.Outer.Inner)", "id"}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.input, func(t *testing.T) {
+			if actual := parsePath(tt.input); !reflect.DeepEqual(tt.expected, actual) {
+				t.Errorf("mismatch: want %+v, got %+v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPathMatches(t *testing.T) {
+	p := fieldPath{"repeated_type", "[1]", "id"}
+
+	if !p.matches(parsePath("repeated_type[*].id")) {
+		t.Errorf("expected %s to match wildcard", p)
+	}
+	if !p.matches(parsePath("repeated_type[1].id")) {
+		t.Errorf("expected %s to match index", p)
+	}
+	if p.matches(parsePath("repeated_type[2].id")) {
+		t.Errorf("expected %s not to match index", p)
+	}
+	if p.matches(parsePath("repeated_type")) {
+		t.Errorf("expected %s not to match prefix", p)
+	}
+}
+
+func TestDiffPath(t *testing.T) {
+	packed := func(m *sample.Outer_Inner) *any.Any {
+		v, err := ptypes.MarshalAny(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	payload := func(v map[string]interface{}) *structpb.Struct {
+		s, err := structpb.NewStruct(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	tests := []struct {
+		name        string
+		expected    *sample.Outer
+		input       *sample.Outer
+		opts        []Option
+		field       string
+		kinds       []StepKind
+		proto       string
+		jsonNames   string
+		jsonPointer string
+	}{
+		{
+			name: "list index",
+			input: makeInput(func(v *sample.Outer) {
+				v.RepeatedType[1].Id = "3"
+			}),
+			field:       "repeated_type.[1].id",
+			kinds:       []StepKind{FieldStep, IndexStep, FieldStep},
+			proto:       "repeated_type[1].id",
+			jsonNames:   "repeatedType[1].id",
+			jsonPointer: "/repeatedType/1/id",
+		},
+		{
+			name: "map key",
+			expected: makeInput(func(v *sample.Outer) {
+				v.MapTypeSimple["a.b/c"] = 1
+			}),
+			input: makeInput(func(v *sample.Outer) {
+				v.MapTypeSimple["a.b/c"] = 2
+			}),
+			field:       "map_type_simple.[a.b/c]",
+			kinds:       []StepKind{FieldStep, MapKeyStep},
+			proto:       `map_type_simple["a.b/c"]`,
+			jsonNames:   `mapTypeSimple["a.b/c"]`,
+			jsonPointer: "/mapTypeSimple/a.b~1c",
+		},
+		{
+			name: "any",
+			expected: makeInput(func(v *sample.Outer) {
+				v.AnyType = packed(&sample.Outer_Inner{Id: "1"})
+			}),
+			input: makeInput(func(v *sample.Outer) {
+				v.AnyType = packed(&sample.Outer_Inner{Id: "2"})
+			}),
+			field:       "any_type.(sample.Outer.Inner).id",
+			kinds:       []StepKind{FieldStep, AnyStep, FieldStep},
+			proto:       "any_type.(sample.Outer.Inner).id",
+			jsonNames:   "anyType.(sample.Outer.Inner).id",
+			jsonPointer: "/anyType/id",
+		},
+		{
+			name: "oneof",
+			input: makeInput(func(v *sample.Outer) {
+				v.OneofType = &sample.Outer_OneofMessage{OneofMessage: &sample.Outer_Inner{Id: "x"}}
+			}),
+			field:       "oneof_type",
+			kinds:       []StepKind{OneofStep},
+			proto:       "oneof_type",
+			jsonNames:   "oneof_type",
+			jsonPointer: "/oneofString",
+		},
+		{
+			name: "list key",
+			input: makeInput(func(v *sample.Outer) {
+				v.RepeatedType[1].Id = "3"
+			}),
+			opts:        []Option{ListKey("repeated_type", "id")},
+			field:       "repeated_type.[id=2]",
+			kinds:       []StepKind{FieldStep, ListKeyStep},
+			proto:       `repeated_type[id="2"]`,
+			jsonNames:   `repeatedType[id="2"]`,
+			jsonPointer: "/repeatedType/id=2",
+		},
+		{
+			name: "struct",
+			expected: makeInput(func(v *sample.Outer) {
+				v.StructType = payload(map[string]interface{}{"user": map[string]interface{}{"tags": []interface{}{"a"}}})
+			}),
+			input: makeInput(func(v *sample.Outer) {
+				v.StructType = payload(map[string]interface{}{"user": map[string]interface{}{"tags": []interface{}{"b"}}})
+			}),
+			field:       "struct_type.user.tags.[0]",
+			kinds:       []StepKind{FieldStep, JSONKeyStep, JSONKeyStep, IndexStep},
+			proto:       "struct_type.user.tags[0]",
+			jsonNames:   "structType.user.tags[0]",
+			jsonPointer: "/structType/user/tags/0",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			expected := tt.expected
+			if expected == nil {
+				expected = makeInput(nil)
+			}
+			d := Equal(expected, tt.input, tt.opts...)
+			if d == nil {
+				t.Fatal("expected a difference")
+			}
+
+			var kinds []StepKind
+			for _, s := range d.Path {
+				kinds = append(kinds, s.Kind)
+			}
+			if !reflect.DeepEqual(tt.kinds, kinds) {
+				t.Errorf("kinds mismatch: want %v, got %v", tt.kinds, kinds)
+			}
+			if d.Field != tt.field {
+				t.Errorf("field mismatch: want %q, got %q", tt.field, d.Field)
+			}
+			if s := d.Path.String(); s != tt.proto {
+				t.Errorf("path mismatch: want %q, got %q", tt.proto, s)
+			}
+			if s := d.Path.JSONString(); s != tt.jsonNames {
+				t.Errorf("json path mismatch: want %q, got %q", tt.jsonNames, s)
+			}
+			if s := d.Path.JSONPointer(); s != tt.jsonPointer {
+				t.Errorf("json pointer mismatch: want %q, got %q", tt.jsonPointer, s)
+			}
+		})
+	}
+}
+
+func TestDiffPathSteps(t *testing.T) {
+	input := makeInput(func(v *sample.Outer) {
+		v.MapType["B"].Id = "XYZ"
+	})
+	input.ProtoReflect().SetUnknown(appendUnknownVarint(nil, 17, 42))
+
+	diffs := EqualAll(makeInput(nil), input)
+	if len(diffs) != 2 {
+		t.Fatalf("expected 2 differences, got %v", diffs)
+	}
+
+	fields := input.ProtoReflect().Descriptor().Fields()
+	mapPath := diffs[0].Path
+	if len(mapPath) != 3 || mapPath[0].Field != fields.ByName("map_type") || mapPath[1].Key.String() != "B" || mapPath[2].Field.Name() != "id" {
+		t.Errorf("unexpected map path %#v", mapPath)
+	}
+	unknownPath := diffs[1].Path
+	if len(unknownPath) != 1 || unknownPath[0].Kind != UnknownFieldStep || unknownPath[0].Number != 17 {
+		t.Errorf("unexpected unknown field path %#v", unknownPath)
+	}
+	if s := unknownPath.String(); s != "#17" {
+		t.Errorf("path mismatch: want %q, got %q", "#17", s)
+	}
+}
+
+func TestDiffPathIgnoreFields(t *testing.T) {
+	payload := func(v map[string]interface{}) *structpb.Struct {
+		s, err := structpb.NewStruct(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	tests := []struct {
+		name     string
+		expected *sample.Outer
+		input    *sample.Outer
+		path     string
+	}{
+		{
+			name: "map key",
+			expected: makeInput(func(v *sample.Outer) {
+				v.MapType["a.b/c"] = &sample.Outer_Inner{Id: "y"}
+			}),
+			input: makeInput(func(v *sample.Outer) {
+				v.MapType["a.b/c"] = &sample.Outer_Inner{Id: "x"}
+			}),
+			path: `map_type["a.b/c"].id`,
+		},
+		{
+			name: "json key",
+			expected: makeInput(func(v *sample.Outer) {
+				v.StructType = payload(map[string]interface{}{"user": map[string]interface{}{"name": "a"}})
+			}),
+			input: makeInput(func(v *sample.Outer) {
+				v.StructType = payload(map[string]interface{}{"user": map[string]interface{}{"name": "b"}})
+			}),
+			path: "struct_type.user.name",
+		},
+		{
+			name: "quoted json key",
+			expected: makeInput(func(v *sample.Outer) {
+				v.StructType = payload(map[string]interface{}{"a.b": map[string]interface{}{"": "a"}})
+			}),
+			input: makeInput(func(v *sample.Outer) {
+				v.StructType = payload(map[string]interface{}{"a.b": map[string]interface{}{"": "b"}})
+			}),
+			path: `struct_type["a.b"][""]`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			d := Equal(tt.expected, tt.input)
+			if d == nil || d.Path.String() != tt.path {
+				t.Fatalf("unexpected difference %v", d)
+			}
+			if d := Equal(tt.expected, tt.input, IgnoreFields(d.Path.String())); d != nil {
+				t.Errorf("expected the rendered path to be ignored, got %v", d)
+			}
+		})
+	}
+}
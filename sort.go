@@ -14,6 +14,16 @@ func SortedMapRange(mapv protoreflect.Map, keyKind protoreflect.Kind, f func(pro
 		keys = append(keys, key)
 		return true
 	})
+	sortMapKeys(keys, keyKind)
+	for _, key := range keys {
+		if !f(key, mapv.Get(key)) {
+			break
+		}
+	}
+}
+
+// sortMapKeys sorts map keys of the given kind in ascending order.
+func sortMapKeys(keys []protoreflect.MapKey, keyKind protoreflect.Kind) {
 	sort.Slice(keys, func(i, j int) bool {
 		switch keyKind {
 		case protoreflect.BoolKind:
@@ -30,8 +40,21 @@ func SortedMapRange(mapv protoreflect.Map, keyKind protoreflect.Kind, f func(pro
 			panic("invalid kind: " + keyKind.String())
 		}
 	})
-	for _, key := range keys {
-		if !f(key, mapv.Get(key)) {
+}
+
+// sortedFieldRange iterates over every populated field in field number order,
+// calling f for each field descriptor and value encountered.
+func sortedFieldRange(m protoreflect.Message, f func(protoreflect.FieldDescriptor, protoreflect.Value) bool) {
+	var fields []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		fields = append(fields, fd)
+		return true
+	})
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Number() < fields[j].Number()
+	})
+	for _, fd := range fields {
+		if !f(fd, m.Get(fd)) {
 			break
 		}
 	}
@@ -0,0 +1,153 @@
+package protocmp
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	structFullName    protoreflect.FullName = "google.protobuf.Struct"
+	valueFullName     protoreflect.FullName = "google.protobuf.Value"
+	listValueFullName protoreflect.FullName = "google.protobuf.ListValue"
+)
+
+// isStructMessage reports whether the message is a google.protobuf.Struct,
+// Value or ListValue.
+func isStructMessage(md protoreflect.MessageDescriptor) bool {
+	switch md.FullName() {
+	case structFullName, valueFullName, listValueFullName:
+		return true
+	default:
+		return false
+	}
+}
+
+// structValue converts a Struct, Value or ListValue message into the value
+// encoding/json would decode from its JSON form.
+func structValue(m protoreflect.Message) interface{} {
+	fields := m.Descriptor().Fields()
+	switch m.Descriptor().FullName() {
+	case structFullName:
+		obj := make(map[string]interface{})
+		m.Get(fields.ByName("fields")).Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			obj[k.String()] = structValue(v.Message())
+			return true
+		})
+		return obj
+	case listValueFullName:
+		list := m.Get(fields.ByName("values")).List()
+		arr := make([]interface{}, list.Len())
+		for i := range arr {
+			arr[i] = structValue(list.Get(i).Message())
+		}
+		return arr
+	default:
+		fd := m.WhichOneof(m.Descriptor().Oneofs().ByName("kind"))
+		if fd == nil {
+			return nil
+		}
+		switch fd.Name() {
+		case "number_value":
+			return m.Get(fd).Float()
+		case "string_value":
+			return m.Get(fd).String()
+		case "bool_value":
+			return m.Get(fd).Bool()
+		case "struct_value", "list_value":
+			return structValue(m.Get(fd).Message())
+		default:
+			return nil
+		}
+	}
+}
+
+// fmtJSON renders a value decoded from JSON as a JSON literal.
+func fmtJSON(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+// equalStruct compares two Struct, Value or ListValue messages as JSON.
+func equalStruct(opts *options, p fieldPath, mx, my protoreflect.Message) matchErrs {
+	return equalJSON(opts, p, structValue(mx), structValue(my))
+}
+
+// equalJSON compares two values decoded from JSON, reporting the differences
+// at JSON-style paths such as "user.name" or "users.[0].name".
+func equalJSON(opts *options, p fieldPath, x, y interface{}) matchErrs {
+	switch vx := x.(type) {
+	case map[string]interface{}:
+		if vy, ok := y.(map[string]interface{}); ok {
+			return equalJSONObject(opts, p, vx, vy)
+		}
+	case []interface{}:
+		if vy, ok := y.([]interface{}); ok {
+			return equalJSONArray(opts, p, vx, vy)
+		}
+	default:
+		if x == y {
+			return nil
+		}
+	}
+
+	return matchErrs{newMatchError("value mismatch").Values(fmtJSON(x), fmtJSON(y))}
+}
+
+// equalJSONObject compares two JSON objects key by key, in sorted key order.
+func equalJSONObject(opts *options, p fieldPath, x, y map[string]interface{}) matchErrs {
+	keys := make([]string, 0, len(x)+len(y))
+	for k := range x {
+		keys = append(keys, k)
+	}
+	for k := range y {
+		if _, ok := x[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var errs matchErrs
+	for _, k := range keys {
+		kp := p.push(jsonKeyPathStep(k))
+		if opts.ignoredPath(kp) {
+			continue
+		}
+
+		vx, okx := x[k]
+		vy, oky := y[k]
+		switch {
+		case !oky:
+			errs = append(errs, newMatchError("missing key").Values(fmtJSON(vx), nil).Step(jsonKeyStep(k)))
+		case !okx:
+			if !opts.partial {
+				errs = append(errs, newMatchError("unexpected key").Values(nil, fmtJSON(vy)).Step(jsonKeyStep(k)))
+			}
+		default:
+			errs = append(errs, equalJSON(opts, kp, vx, vy).Step(jsonKeyStep(k))...)
+		}
+	}
+	return errs
+}
+
+// equalJSONArray compares two JSON arrays element by element.
+func equalJSONArray(opts *options, p fieldPath, x, y []interface{}) matchErrs {
+	if len(x) != len(y) && !(opts.partial && len(x) < len(y)) {
+		return matchErrs{newMatchError("length mismatch").Values(len(x), len(y))}
+	}
+
+	var errs matchErrs
+	for i := range x {
+		ip := p.push(fmt.Sprintf("[%d]", i))
+		if opts.ignoredPath(ip) {
+			continue
+		}
+		errs = append(errs, equalJSON(opts, ip, x[i], y[i]).Step(indexStep(i))...)
+	}
+	return errs
+}
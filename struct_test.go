@@ -0,0 +1,120 @@
+package protocmp
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/nbaztec/protocmp/protos/sample"
+)
+
+func TestAssertStruct(t *testing.T) {
+	payload := func(v map[string]interface{}) *structpb.Struct {
+		s, err := structpb.NewStruct(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	expected := makeInput(func(v *sample.Outer) {
+		v.StructType = payload(map[string]interface{}{
+			"user": map[string]interface{}{
+				"name": "foo",
+				"tags": []interface{}{"a", "b"},
+			},
+			"count": 1,
+			"valid": true,
+		})
+	})
+
+	tests := []struct {
+		name  string
+		input *sample.Outer
+		diffs DiffErrors
+	}{
+		{
+			name: "equal",
+			input: makeInput(func(v *sample.Outer) {
+				v.StructType = payload(map[string]interface{}{
+					"valid": true,
+					"count": 1,
+					"user": map[string]interface{}{
+						"tags": []interface{}{"a", "b"},
+						"name": "foo",
+					},
+				})
+			}),
+		},
+		{
+			name: "nil value",
+			input: makeInput(func(v *sample.Outer) {
+				v.StructType = nil
+			}),
+			diffs: DiffErrors{
+				{
+					Field:    "struct_type",
+					Message:  "value mismatch",
+					Expected: `{"count":1,"user":{"name":"foo","tags":["a","b"]},"valid":true}`,
+					Actual:   `<nil>`,
+				},
+			},
+		},
+		{
+			name: "different values",
+			input: makeInput(func(v *sample.Outer) {
+				v.StructType = payload(map[string]interface{}{
+					"user": map[string]interface{}{
+						"name": "bar",
+						"tags": []interface{}{"a", 2},
+						"age":  nil,
+					},
+					"count": "1",
+				})
+			}),
+			diffs: DiffErrors{
+				{
+					Field:    "struct_type.count",
+					Message:  "value mismatch",
+					Expected: `1`,
+					Actual:   `"1"`,
+				},
+				{
+					Field:    "struct_type.user.age",
+					Message:  "unexpected key",
+					Expected: `<nil>`,
+					Actual:   `null`,
+				},
+				{
+					Field:    "struct_type.user.name",
+					Message:  "value mismatch",
+					Expected: `"foo"`,
+					Actual:   `"bar"`,
+				},
+				{
+					Field:    "struct_type.user.tags.[1]",
+					Message:  "value mismatch",
+					Expected: `"b"`,
+					Actual:   `2`,
+				},
+				{
+					Field:    "struct_type.valid",
+					Message:  "missing key",
+					Expected: `true`,
+					Actual:   `<nil>`,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual := withoutPaths(tt.diffs, EqualAll(expected, tt.input))
+			if !reflect.DeepEqual(tt.diffs, actual) {
+				t.Errorf("mismatch err\n++ want:\n%s\n-- got:\n%s", tt.diffs, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package protocmp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// longTextThreshold is the length above which mismatching strings are
+	// reported as a unified diff instead of two quoted strings.
+	longTextThreshold = 80
+	// unifiedDiffContext is the number of unchanged lines shown around the
+	// changed lines of a unified diff.
+	unifiedDiffContext = 3
+)
+
+// text is a string value that is rendered quoted.
+type text string
+
+func (t text) String() string {
+	return strconv.Quote(string(t))
+}
+
+// isLongText reports whether a string is reported as a unified diff, i.e. if
+// it spans multiple lines or exceeds longTextThreshold.
+func isLongText(s string) bool {
+	return len(s) > longTextThreshold || strings.Contains(s, "\n")
+}
+
+// lineOp is a line of a line-based diff, marked as in DiffError.Error: '+' for
+// a line of the expected text only, '-' for a line of the actual text only and
+// ' ' for a line of both.
+type lineOp struct {
+	mark byte
+	line string
+}
+
+// diffLines aligns the lines of x and y with align.
+func diffLines(x, y []string) []lineOp {
+	ops := align(len(x), len(y), func(i, j int) bool {
+		return x[i] == y[j]
+	})
+
+	lines := make([]lineOp, 0, len(ops))
+	i, j := 0, 0
+	for _, op := range ops {
+		switch op {
+		case editMatch:
+			lines = append(lines, lineOp{' ', x[i]})
+			i++
+			j++
+		case editDelete:
+			lines = append(lines, lineOp{'+', x[i]})
+			i++
+		case editInsert:
+			lines = append(lines, lineOp{'-', y[j]})
+			j++
+		}
+	}
+	return lines
+}
+
+// unifiedDiff renders a line-based diff of the expected text x and the actual
+// text y in hunks of changed lines with unifiedDiffContext lines of context,
+// each introduced by a header "@@ +l,s -l,s @@" giving the start line and
+// size of the hunk in x and in y.
+func unifiedDiff(x, y string) string {
+	ops := diffLines(strings.Split(x, "\n"), strings.Split(y, "\n"))
+
+	var hunks []string
+	xl, yl := 0, 0
+	for i := 0; i < len(ops); {
+		if ops[i].mark == ' ' {
+			xl++
+			yl++
+			i++
+			continue
+		}
+
+		start := i - unifiedDiffContext
+		if start < 0 {
+			start = 0
+		}
+		end := i + 1
+		for j := i; j < len(ops) && j-end < 2*unifiedDiffContext; j++ {
+			if ops[j].mark != ' ' {
+				end = j + 1
+			}
+		}
+		stop := end + unifiedDiffContext
+		if stop > len(ops) {
+			stop = len(ops)
+		}
+
+		// Lines of the leading context were already counted.
+		xs, ys := xl-(i-start), yl-(i-start)
+		var lines []string
+		xn, yn := 0, 0
+		for _, op := range ops[start:stop] {
+			lines = append(lines, string(op.mark)+op.line)
+			if op.mark != '-' {
+				xn++
+			}
+			if op.mark != '+' {
+				yn++
+			}
+		}
+		hunks = append(hunks, fmt.Sprintf("@@ +%d,%d -%d,%d @@\n%s", xs+1, xn, ys+1, yn, strings.Join(lines, "\n")))
+
+		xl, yl = xs+xn, ys+yn
+		i = stop
+	}
+	return strings.Join(hunks, "\n")
+}
@@ -0,0 +1,128 @@
+package protocmp
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/nbaztec/protocmp/protos/sample"
+)
+
+func TestAssertLongString(t *testing.T) {
+	query := "SELECT id, name\nFROM users\nWHERE active = 1\nORDER BY id"
+	changed := "SELECT id, name\nFROM users\nWHERE active = 0\nORDER BY id"
+
+	expected := makeInput(func(v *sample.Outer) {
+		v.StrVal = query
+	})
+	actual := makeInput(func(v *sample.Outer) {
+		v.StrVal = changed
+	})
+
+	diff := &DiffError{
+		Field:    "str_val",
+		Message:  "value mismatch",
+		Expected: `"SELECT id, name\nFROM users\nWHERE active = 1\nORDER BY id"`,
+		Actual:   `"SELECT id, name\nFROM users\nWHERE active = 0\nORDER BY id"`,
+		Diff: "@@ +1,4 -1,4 @@\n" +
+			" SELECT id, name\n" +
+			" FROM users\n" +
+			"+WHERE active = 1\n" +
+			"-WHERE active = 0\n" +
+			" ORDER BY id",
+	}
+	if err := withoutPath(diff, Equal(expected, actual)); !reflect.DeepEqual(diff, err) {
+		t.Errorf("mismatch err\n++ want:\n%s\n-- got:\n%s", diff, err)
+	}
+
+	expectedErr := "str_val: value mismatch\n" + diff.Diff
+	if err := Equal(expected, actual); err.Error() != expectedErr {
+		t.Errorf("expected %s, got %s", expectedErr, err.Error())
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	lines := func(from, to int) []string {
+		var s []string
+		for i := from; i <= to; i++ {
+			s = append(s, strings.Repeat("x", i))
+		}
+		return s
+	}
+	numbered := func(n int) []string {
+		s := make([]string, n)
+		for i := range s {
+			s[i] = "line " + strconv.Itoa(i+1)
+		}
+		return s
+	}
+	join := func(parts ...[]string) string {
+		var s []string
+		for _, p := range parts {
+			s = append(s, p...)
+		}
+		return strings.Join(s, "\n")
+	}
+
+	tests := []struct {
+		name     string
+		x, y     string
+		expected string
+	}{
+		{
+			name:     "single line",
+			x:        "foo",
+			y:        "bar",
+			expected: "@@ +1,1 -1,1 @@\n+foo\n-bar",
+		},
+		{
+			name: "separate hunks",
+			x:    join(lines(1, 20)),
+			y:    join([]string{"a"}, lines(2, 19), []string{"b"}),
+			expected: "@@ +1,4 -1,4 @@\n" +
+				"+x\n" +
+				"-a\n" +
+				" xx\n" +
+				" xxx\n" +
+				" xxxx\n" +
+				"@@ +17,4 -17,4 @@\n" +
+				" " + strings.Repeat("x", 17) + "\n" +
+				" " + strings.Repeat("x", 18) + "\n" +
+				" " + strings.Repeat("x", 19) + "\n" +
+				"+" + strings.Repeat("x", 20) + "\n" +
+				"-b",
+		},
+		{
+			name: "insertion",
+			x:    join(lines(1, 4)),
+			y:    join(lines(1, 2), []string{"new"}, lines(3, 4)),
+			expected: "@@ +1,4 -1,5 @@\n" +
+				" x\n" +
+				" xx\n" +
+				"-new\n" +
+				" xxx\n" +
+				" xxxx",
+		},
+		{
+			name: "large text",
+			x:    join(numbered(5000)),
+			y:    join(numbered(4999), []string{"changed"}),
+			expected: "@@ +4997,4 -4997,4 @@\n" +
+				" line 4997\n" +
+				" line 4998\n" +
+				" line 4999\n" +
+				"+line 5000\n" +
+				"-changed",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff(tt.x, tt.y); got != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, got)
+			}
+		})
+	}
+}
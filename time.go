@@ -0,0 +1,112 @@
+package protocmp
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	timestampFullName protoreflect.FullName = "google.protobuf.Timestamp"
+	durationFullName  protoreflect.FullName = "google.protobuf.Duration"
+)
+
+// timeTolerance is the maximum accepted distance between two timestamps or
+// durations, optionally restricted to a set of field paths.
+type timeTolerance struct {
+	value time.Duration
+	paths []fieldPath
+}
+
+// appliesTo reports whether the tolerance is restricted to the given path.
+func (t timeTolerance) appliesTo(p fieldPath) bool {
+	return matchesAny(p, t.paths) || matchesAny(p.field(), t.paths)
+}
+
+// isTimeMessage reports whether the message is a google.protobuf.Timestamp or
+// a google.protobuf.Duration.
+func isTimeMessage(md protoreflect.MessageDescriptor) bool {
+	return md.FullName() == timestampFullName || md.FullName() == durationFullName
+}
+
+// timeValue returns the seconds and nanos of a Timestamp or Duration message,
+// normalized so that nanos is within [0, 1e9).
+func timeValue(m protoreflect.Message) (int64, int64) {
+	fields := m.Descriptor().Fields()
+	seconds := m.Get(fields.ByName("seconds")).Int()
+	nanos := m.Get(fields.ByName("nanos")).Int()
+	seconds += nanos / int64(time.Second)
+	nanos %= int64(time.Second)
+	if nanos < 0 {
+		nanos += int64(time.Second)
+		seconds--
+	}
+	return seconds, nanos
+}
+
+// timeDelta returns the absolute difference between two normalized times as
+// whole seconds and nanos within [0, 1e9). Unlike time.Duration it cannot
+// overflow for any number of seconds.
+func timeDelta(sx, nx, sy, ny int64) (uint64, int64) {
+	if sx < sy || (sx == sy && nx < ny) {
+		sx, nx, sy, ny = sy, ny, sx, nx
+	}
+	seconds := uint64(sx) - uint64(sy)
+	nanos := nx - ny
+	if nanos < 0 {
+		nanos += int64(time.Second)
+		seconds--
+	}
+	return seconds, nanos
+}
+
+// fmtSpan renders a span of seconds and nanos like time.Duration, falling back
+// to fractional seconds beyond the range of time.Duration.
+func fmtSpan(negative bool, seconds uint64, nanos int64) string {
+	var s string
+	if seconds < math.MaxInt64/uint64(time.Second) {
+		s = (time.Duration(seconds)*time.Second + time.Duration(nanos)).String()
+	} else {
+		s = strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%d.%09d", seconds, nanos), "0"), ".") + "s"
+	}
+	if negative {
+		return "-" + s
+	}
+	return s
+}
+
+// fmtTime renders a Timestamp as RFC 3339 and a Duration as a Go duration.
+func fmtTime(m protoreflect.Message) string {
+	seconds, nanos := timeValue(m)
+	if m.Descriptor().FullName() == timestampFullName {
+		return time.Unix(seconds, nanos).UTC().Format(time.RFC3339Nano)
+	}
+	ds, dn := timeDelta(seconds, nanos, 0, 0)
+	return fmtSpan(seconds < 0, ds, dn)
+}
+
+// equalTime compares two Timestamp or Duration messages as points in time or
+// spans of time, accepting differences within the tolerance for their path.
+func equalTime(opts *options, p fieldPath, mx, my protoreflect.Message) matchErrs {
+	sx, nx := timeValue(mx)
+	sy, ny := timeValue(my)
+	if sx == sy && nx == ny {
+		return nil
+	}
+
+	tolerance, ok := opts.timeTolerance(p)
+	if !ok {
+		return matchErrs{newMatchError("value mismatch").Values(fmtTime(mx), fmtTime(my))}
+	}
+	ds, dn := timeDelta(sx, nx, sy, ny)
+	ts, tn := uint64(tolerance/time.Second), int64(tolerance%time.Second)
+	if tolerance >= 0 && (ds < ts || (ds == ts && dn <= tn)) {
+		return nil
+	}
+
+	message := fmt.Sprintf("value mismatch (delta %s exceeds tolerance %s)", fmtSpan(false, ds, dn), tolerance)
+	return matchErrs{newMatchError(message).Values(fmtTime(mx), fmtTime(my))}
+}
@@ -0,0 +1,140 @@
+package protocmp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/duration"
+	"github.com/golang/protobuf/ptypes/timestamp"
+
+	"github.com/nbaztec/protocmp/protos/sample"
+)
+
+func TestTimeTolerance(t *testing.T) {
+	tests := []struct {
+		name  string
+		input *sample.Outer
+		opt   Option
+		diff  *DiffError
+	}{
+		{
+			name: "timestamp - within",
+			input: makeInput(func(v *sample.Outer) {
+				v.TimestampType, _ = ptypes.TimestampProto(time.Date(2020, time.August, 30, 19, 05, 00, 999, time.UTC))
+			}),
+			opt: TimeTolerance(time.Second),
+		},
+		{
+			name: "timestamp - exceeded",
+			input: makeInput(func(v *sample.Outer) {
+				v.TimestampType, _ = ptypes.TimestampProto(time.Date(2020, time.August, 30, 19, 05, 02, 00, time.UTC))
+			}),
+			opt: TimeTolerance(time.Second),
+			diff: &DiffError{
+				Field:    "timestamp_type",
+				Message:  "value mismatch (delta 2s exceeds tolerance 1s)",
+				Expected: `2020-08-30T19:05:00Z`,
+				Actual:   `2020-08-30T19:05:02Z`,
+			},
+		},
+		{
+			name: "duration - within",
+			input: makeInput(func(v *sample.Outer) {
+				v.DurationType = ptypes.DurationProto(1500 * time.Millisecond)
+			}),
+			opt: TimeTolerance(time.Second, "duration_type"),
+		},
+		{
+			name: "duration - other field",
+			input: makeInput(func(v *sample.Outer) {
+				v.DurationType = ptypes.DurationProto(1500 * time.Millisecond)
+			}),
+			opt: TimeTolerance(time.Second, "timestamp_type"),
+			diff: &DiffError{
+				Field:    "duration_type",
+				Message:  "value mismatch",
+				Expected: `1s`,
+				Actual:   `1.5s`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			check(
+				t,
+				makeInput(nil),
+				tt.input,
+				tt.diff,
+				tt.opt,
+			)
+		})
+	}
+}
+
+func TestTimeBeyondDurationRange(t *testing.T) {
+	const year = 365 * 24 * 60 * 60
+
+	tests := []struct {
+		name     string
+		expected *sample.Outer
+		input    *sample.Outer
+		opt      Option
+		diff     *DiffError
+	}{
+		{
+			name: "timestamp - wrapped nanoseconds",
+			expected: makeInput(func(v *sample.Outer) {
+				v.TimestampType = &timestamp.Timestamp{Seconds: 0}
+			}),
+			input: makeInput(func(v *sample.Outer) {
+				v.TimestampType = &timestamp.Timestamp{Seconds: 18446744073, Nanos: 709551616}
+			}),
+			diff: &DiffError{
+				Field:    "timestamp_type",
+				Message:  "value mismatch",
+				Expected: `1970-01-01T00:00:00Z`,
+				Actual:   `2554-07-21T23:34:33.709551616Z`,
+			},
+		},
+		{
+			name: "timestamp - within tolerance",
+			expected: makeInput(func(v *sample.Outer) {
+				v.TimestampType = &timestamp.Timestamp{Seconds: -300 * year}
+			}),
+			input: makeInput(func(v *sample.Outer) {
+				v.TimestampType = &timestamp.Timestamp{Seconds: -300*year + 1}
+			}),
+			opt: TimeTolerance(time.Second),
+		},
+		{
+			name: "duration - exceeded",
+			expected: makeInput(func(v *sample.Outer) {
+				v.DurationType = &duration.Duration{Seconds: 300 * year}
+			}),
+			input: makeInput(func(v *sample.Outer) {
+				v.DurationType = &duration.Duration{Seconds: -300 * year, Nanos: -500000000}
+			}),
+			opt: TimeTolerance(time.Second),
+			diff: &DiffError{
+				Field:    "duration_type",
+				Message:  "value mismatch (delta 18921600000.5s exceeds tolerance 1s)",
+				Expected: `9460800000s`,
+				Actual:   `-9460800000.5s`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []Option
+			if tt.opt != nil {
+				opts = append(opts, tt.opt)
+			}
+			check(t, tt.expected, tt.input, tt.diff, opts...)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package protocmp
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Transformer rewrites a singular scalar value before it is compared.
+type Transformer func(v protoreflect.Value) protoreflect.Value
+
+// LowerCase is a Transformer that lower-cases string values.
+func LowerCase(v protoreflect.Value) protoreflect.Value {
+	if s, ok := v.Interface().(string); ok {
+		return protoreflect.ValueOfString(strings.ToLower(s))
+	}
+	return v
+}
+
+// TrimSpace is a Transformer that removes leading and trailing white space
+// from string values.
+func TrimSpace(v protoreflect.Value) protoreflect.Value {
+	if s, ok := v.Interface().(string); ok {
+		return protoreflect.ValueOfString(strings.TrimSpace(s))
+	}
+	return v
+}
+
+// RoundFloat returns a Transformer that rounds float and double values to the
+// given number of decimal digits.
+func RoundFloat(digits int) Transformer {
+	scale := math.Pow10(digits)
+	return func(v protoreflect.Value) protoreflect.Value {
+		switch f := v.Interface().(type) {
+		case float32:
+			return protoreflect.ValueOfFloat32(float32(math.Round(float64(f)*scale) / scale))
+		case float64:
+			return protoreflect.ValueOfFloat64(math.Round(f*scale) / scale)
+		}
+		return v
+	}
+}
+
+// transformer is a Transformer restricted to a set of field paths or kinds.
+type transformer struct {
+	t     Transformer
+	paths []fieldPath
+	kinds []protoreflect.Kind
+}
+
+// appliesTo reports whether the transformer applies to the value of the given
+// field at the given path.
+func (t transformer) appliesTo(p fieldPath, fd protoreflect.FieldDescriptor) bool {
+	if matchesAny(p, t.paths) || matchesAny(p.field(), t.paths) {
+		return true
+	}
+	for _, kind := range t.kinds {
+		if fd.Kind() == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldTransformers returns the transformers for the field at the given path
+// if it is a singular scalar field, so that a field set on one side only can be
+// compared by value with the default of the unset side.
+func fieldTransformers(opts *options, p fieldPath, fd protoreflect.FieldDescriptor) []Transformer {
+	if fd.IsList() || fd.IsMap() || fd.Message() != nil {
+		return nil
+	}
+	return opts.transformers(p, fd)
+}
+
+// equalTransformed compares two scalar values after applying the transformers
+// to both of them. Differences report the transformed values, with the original
+// ones in the message.
+func equalTransformed(opts *options, p fieldPath, fd protoreflect.FieldDescriptor, transformers []Transformer, x, y protoreflect.Value) matchErrs {
+	tx, ty := x, y
+	for _, t := range transformers {
+		tx = t(tx)
+		ty = t(ty)
+	}
+
+	errs := equalScalar(opts, p, fd, tx, ty)
+	for _, err := range errs {
+		err.message = fmt.Sprintf("%s (original %s, %s)", err.message, fmtValue(x, fd), fmtValue(y, fd))
+	}
+	return errs
+}
+
+// sortedList is a read-only view of a list with its elements in sorted order.
+type sortedList struct {
+	protoreflect.List
+	order []int
+}
+
+// sortList returns a view of the list of scalars sorted by value. Lists of
+// messages are returned as is.
+func sortList(fd protoreflect.FieldDescriptor, list protoreflect.List) protoreflect.List {
+	if fd.Message() != nil {
+		return list
+	}
+
+	order := make([]int, list.Len())
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return lessScalar(fd.Kind(), list.Get(order[i]), list.Get(order[j]))
+	})
+	return sortedList{List: list, order: order}
+}
+
+func (l sortedList) Get(i int) protoreflect.Value {
+	return l.List.Get(l.order[i])
+}
+
+// lessScalar reports whether the scalar value x sorts before y.
+func lessScalar(kind protoreflect.Kind, x, y protoreflect.Value) bool {
+	switch kind {
+	case protoreflect.BoolKind:
+		return !x.Bool() && y.Bool()
+	case protoreflect.EnumKind:
+		return x.Enum() < y.Enum()
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return x.Int() < y.Int()
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return x.Uint() < y.Uint()
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return x.Float() < y.Float()
+	case protoreflect.StringKind:
+		return x.String() < y.String()
+	case protoreflect.BytesKind:
+		return bytes.Compare(x.Bytes(), y.Bytes()) < 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,173 @@
+package protocmp
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/nbaztec/protocmp/protos/sample"
+)
+
+func TestTransform(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected *sample.Outer
+		input    *sample.Outer
+		opts     []Option
+		diff     *DiffError
+	}{
+		{
+			name: "lower case path",
+			input: makeInput(func(v *sample.Outer) {
+				v.StrVal = "FOO"
+			}),
+			opts: []Option{TransformPaths(LowerCase, "str_val")},
+		},
+		{
+			name: "trim space list elements",
+			input: makeInput(func(v *sample.Outer) {
+				v.RepeatedType[0].Id = " 1 "
+			}),
+			opts: []Option{TransformPaths(TrimSpace, "repeated_type[*].id")},
+		},
+		{
+			name: "round kind",
+			input: makeInput(func(v *sample.Outer) {
+				v.DoubleVal = 1.104
+			}),
+			opts: []Option{TransformKinds(RoundFloat(2), protoreflect.DoubleKind)},
+		},
+		{
+			name: "chained",
+			input: makeInput(func(v *sample.Outer) {
+				v.MapType["A"].Id = " aa"
+			}),
+			opts: []Option{TransformKinds(TrimSpace, protoreflect.StringKind), TransformKinds(LowerCase, protoreflect.StringKind)},
+		},
+		{
+			name: "unset field",
+			expected: makeInput(func(v *sample.Outer) {
+				v.StrVal = ""
+			}),
+			input: makeInput(func(v *sample.Outer) {
+				v.StrVal = "  "
+			}),
+			opts: []Option{TransformPaths(TrimSpace, "str_val")},
+		},
+		{
+			name: "sort repeated",
+			input: makeInput(func(v *sample.Outer) {
+				v.RepeatedTypeSimple = []int32{11, 10, 9}
+			}),
+			opts: []Option{SortRepeated("repeated_type_simple")},
+		},
+		{
+			name: "sort repeated - different value",
+			input: makeInput(func(v *sample.Outer) {
+				v.RepeatedTypeSimple = []int32{11, 12, 9}
+			}),
+			opts: []Option{SortRepeated("repeated_type_simple")},
+			diff: &DiffError{
+				Field:    "repeated_type_simple.[1]",
+				Message:  "value mismatch",
+				Expected: `10`,
+				Actual:   `11`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			expected := tt.expected
+			if expected == nil {
+				expected = makeInput(nil)
+			}
+			check(
+				t,
+				expected,
+				tt.input,
+				tt.diff,
+				tt.opts...,
+			)
+		})
+	}
+}
+
+func TestTransformOriginalValues(t *testing.T) {
+	dropLast := func(v protoreflect.Value) protoreflect.Value {
+		if b, ok := v.Interface().([]byte); ok && len(b) > 0 {
+			return protoreflect.ValueOfBytes(b[:len(b)-1])
+		}
+		return v
+	}
+
+	tests := []struct {
+		name     string
+		expected *sample.Outer
+		input    *sample.Outer
+		opts     []Option
+		diff     *DiffError
+	}{
+		{
+			name: "string",
+			input: makeInput(func(v *sample.Outer) {
+				v.StrVal = "BAR"
+			}),
+			opts: []Option{TransformPaths(LowerCase, "str_val")},
+			diff: &DiffError{
+				Field:    "str_val",
+				Message:  `value mismatch (original "foo", "BAR")`,
+				Expected: `"foo"`,
+				Actual:   `"bar"`,
+			},
+		},
+		{
+			name: "bytes",
+			expected: makeInput(func(v *sample.Outer) {
+				v.BytesVal = []byte{0x1, 0x2}
+			}),
+			input: makeInput(func(v *sample.Outer) {
+				v.BytesVal = []byte{0x3, 0x2}
+			}),
+			opts: []Option{TransformPaths(dropLast, "bytes_val")},
+			diff: &DiffError{
+				Field:    "bytes_val",
+				Message:  `value mismatch (len 1 vs 1, first difference at offset 0) (original "\x01\x02", "\x03\x02")`,
+				Expected: `00000000  01                                                |.|`,
+				Actual:   `00000000  03                                                |.|`,
+			},
+		},
+		{
+			name: "unset field",
+			expected: makeInput(func(v *sample.Outer) {
+				v.StrVal = ""
+			}),
+			input: makeInput(func(v *sample.Outer) {
+				v.StrVal = " Bar"
+			}),
+			opts: []Option{TransformPaths(TrimSpace, "str_val")},
+			diff: &DiffError{
+				Field:    "str_val",
+				Message:  `value mismatch (original "", " Bar")`,
+				Expected: `""`,
+				Actual:   `"Bar"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			expected := tt.expected
+			if expected == nil {
+				expected = makeInput(nil)
+			}
+			err := withoutPath(tt.diff, Equal(expected, tt.input, tt.opts...))
+			if !reflect.DeepEqual(tt.diff, err) {
+				t.Errorf("mismatch err\n++ want:\n%s\n-- got:\n%s", tt.diff, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package protocmp
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// unknownField is a single field of the unknown fields of a message.
+type unknownField struct {
+	num protowire.Number
+	typ protowire.Type
+	raw protoreflect.RawFields // The field including its tag.
+}
+
+// String renders the field with its number, wire type and decoded value,
+// e.g. #17(varint)=42 or #20(bytes)="foo".
+func (f unknownField) String() string {
+	if f.num == 0 {
+		return fmt.Sprintf("#?(malformed)=%s", strconv.Quote(string(f.raw)))
+	}
+	_, _, n := protowire.ConsumeTag(f.raw)
+	return fmt.Sprintf("#%d(%s)=%s", f.num, fmtWireType(f.typ), fmtWireValue(f.num, f.typ, f.raw[n:]))
+}
+
+func fmtWireType(typ protowire.Type) string {
+	switch typ {
+	case protowire.VarintType:
+		return "varint"
+	case protowire.Fixed32Type:
+		return "fixed32"
+	case protowire.Fixed64Type:
+		return "fixed64"
+	case protowire.BytesType:
+		return "bytes"
+	case protowire.StartGroupType:
+		return "group"
+	default:
+		return strconv.Itoa(int(typ))
+	}
+}
+
+// fmtWireValue renders the value of a field following its tag.
+func fmtWireValue(num protowire.Number, typ protowire.Type, b []byte) string {
+	switch typ {
+	case protowire.VarintType:
+		v, _ := protowire.ConsumeVarint(b)
+		return strconv.FormatUint(v, 10)
+	case protowire.Fixed32Type:
+		v, _ := protowire.ConsumeFixed32(b)
+		return strconv.FormatUint(uint64(v), 10)
+	case protowire.Fixed64Type:
+		v, _ := protowire.ConsumeFixed64(b)
+		return strconv.FormatUint(v, 10)
+	case protowire.BytesType:
+		v, _ := protowire.ConsumeBytes(b)
+		return fmtWireBytes(v)
+	case protowire.StartGroupType:
+		v, _ := protowire.ConsumeGroup(num, b)
+		return fmtUnknown(v)
+	default:
+		return strconv.Quote(string(b))
+	}
+}
+
+// fmtWireBytes renders a length-delimited value as a quoted string if it is
+// printable text, as a sub-message if it decodes as one and as a quoted
+// string of bytes otherwise.
+func fmtWireBytes(b []byte) string {
+	if isPrintable(b) {
+		return strconv.Quote(string(b))
+	}
+	if fields, ok := parseUnknown(b); ok {
+		return fmtUnknownFields(fields)
+	}
+	return strconv.Quote(string(b))
+}
+
+func isPrintable(b []byte) bool {
+	if !utf8.Valid(b) {
+		return false
+	}
+	for _, r := range string(b) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// fmtUnknown renders raw fields as a message of decoded fields.
+func fmtUnknown(b protoreflect.RawFields) string {
+	fields, _ := parseUnknown(b)
+	return fmtUnknownFields(fields)
+}
+
+func fmtUnknownFields(fields []unknownField) string {
+	s := make([]string, len(fields))
+	for i, f := range fields {
+		s[i] = f.String()
+	}
+	return "<" + strings.Join(s, " ") + ">"
+}
+
+// parseUnknown splits raw fields into individual fields in wire order.
+// Malformed trailing data is kept as a field of number 0 so that it is still
+// compared, in which case ok is false.
+func parseUnknown(b []byte) (fields []unknownField, ok bool) {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeField(b)
+		if n < 0 {
+			return append(fields, unknownField{raw: b}), false
+		}
+		fields = append(fields, unknownField{num: num, typ: typ, raw: b[:n]})
+		b = b[n:]
+	}
+	return fields, true
+}
+
+// groupUnknown splits unknown fields by field number, keeping the wire order
+// of the fields of each number or sorting them by their contents.
+func groupUnknown(b protoreflect.RawFields, sorted bool) map[protowire.Number][]unknownField {
+	m := make(map[protowire.Number][]unknownField)
+	fields, _ := parseUnknown(b)
+	for _, f := range fields {
+		m[f.num] = append(m[f.num], f)
+	}
+	if sorted {
+		for _, fields := range m {
+			sort.Slice(fields, func(i, j int) bool {
+				return bytes.Compare(fields[i].raw, fields[j].raw) < 0
+			})
+		}
+	}
+	return m
+}
+
+// unknownSteps prepends the path of the i-th unknown field of a number to the
+// error, which is only indexed if the number occurs more than once.
+func unknownSteps(err *matchErr, num protowire.Number, i int, xs, ys []unknownField) *matchErr {
+	if len(xs) > 1 || len(ys) > 1 {
+		err.Step(indexStep(i))
+	}
+	return err.Step(unknownFieldStep(num))
+}
+
+// equalUnknown compares unknown fields decoded field by field, reporting each
+// differing field on its own. The fields of each number are compared in wire
+// order, or as a multiset with UnorderedUnknown. Unknown fields have no name
+// to match a mask against, so they are never compared under a mask.
+func equalUnknown(opts *options, x, y protoreflect.RawFields) matchErrs {
+	if opts.ignoreUnknown || len(opts.mask) > 0 || bytes.Equal(x, y) {
+		return nil
+	}
+
+	fx := groupUnknown(x, opts.unorderedUnknown)
+	fy := groupUnknown(y, opts.unorderedUnknown)
+	nums := make([]protowire.Number, 0, len(fx)+len(fy))
+	for num := range fx {
+		nums = append(nums, num)
+	}
+	for num := range fy {
+		if _, ok := fx[num]; !ok {
+			nums = append(nums, num)
+		}
+	}
+	sort.Slice(nums, func(i, j int) bool { return nums[i] < nums[j] })
+
+	var errs matchErrs
+	for _, num := range nums {
+		if opts.unorderedUnknown {
+			errs = append(errs, equalUnknownUnordered(opts, num, fx[num], fy[num])...)
+		} else {
+			errs = append(errs, equalUnknownOrdered(opts, num, fx[num], fy[num])...)
+		}
+	}
+
+	if len(errs) == 0 && !opts.unorderedUnknown && !opts.partial {
+		// The fields of each number match, but are interleaved differently.
+		return matchErrs{newMatchError("order mismatch").Values(fmtUnknown(x), fmtUnknown(y))}
+	}
+	return errs
+}
+
+// equalUnknownOrdered compares the unknown fields of a number by position.
+func equalUnknownOrdered(opts *options, num protowire.Number, xs, ys []unknownField) matchErrs {
+	var errs matchErrs
+	for i := 0; i < len(xs) || i < len(ys); i++ {
+		switch {
+		case i >= len(ys):
+			errs = append(errs, unknownSteps(newMatchError("missing field").Values(xs[i], nil), num, i, xs, ys))
+		case i >= len(xs):
+			if !opts.partial {
+				errs = append(errs, unknownSteps(newMatchError("unexpected field").Values(nil, ys[i]), num, i, xs, ys))
+			}
+		case !bytes.Equal(xs[i].raw, ys[i].raw):
+			errs = append(errs, unknownSteps(newMatchError("value mismatch").Values(xs[i], ys[i]), num, i, xs, ys))
+		}
+	}
+	return errs
+}
+
+// equalUnknownUnordered compares the unknown fields of a number as a
+// multiset, ignoring the order they appear in on the wire.
+func equalUnknownUnordered(opts *options, num protowire.Number, xs, ys []unknownField) matchErrs {
+	var errs matchErrs
+	matched := make([]bool, len(ys))
+	for _, fx := range xs {
+		found := false
+		for j, fy := range ys {
+			if !matched[j] && bytes.Equal(fx.raw, fy.raw) {
+				matched[j] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs = append(errs, newMatchError("missing field").Step(unknownFieldStep(num)).Values(fx, nil))
+		}
+	}
+	if opts.partial {
+		return errs
+	}
+	for j, fy := range ys {
+		if !matched[j] {
+			errs = append(errs, newMatchError("unexpected field").Step(unknownFieldStep(num)).Values(nil, fy))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,120 @@
+package protocmp
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func appendUnknownBytes(b []byte, num protowire.Number, v []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+func TestEqualUnknown(t *testing.T) {
+	nested := appendUnknownBytes(appendUnknownVarint(nil, 1, 7), 2, []byte("x"))
+
+	tests := []struct {
+		name     string
+		expected []byte
+		actual   []byte
+		opts     []Option
+		diffs    DiffErrors
+	}{
+		{
+			name:     "equal",
+			expected: appendUnknownVarint(nil, 17, 42),
+			actual:   appendUnknownVarint(nil, 17, 42),
+		},
+		{
+			name:     "varint mismatch",
+			expected: appendUnknownVarint(appendUnknownVarint(nil, 17, 42), 18, 1),
+			actual:   appendUnknownVarint(appendUnknownVarint(nil, 17, 43), 18, 1),
+			diffs: DiffErrors{
+				{Field: "#17", Message: "value mismatch", Expected: "#17(varint)=42", Actual: "#17(varint)=43", Path: Path{unknownFieldStep(17)}},
+			},
+		},
+		{
+			name:     "missing and unexpected",
+			expected: appendUnknownVarint(nil, 17, 42),
+			actual:   appendUnknownBytes(nil, 20, []byte("foo")),
+			diffs: DiffErrors{
+				{Field: "#17", Message: "missing field", Expected: "#17(varint)=42", Actual: "<nil>", Path: Path{unknownFieldStep(17)}},
+				{Field: "#20", Message: "unexpected field", Expected: "<nil>", Actual: `#20(bytes)="foo"`, Path: Path{unknownFieldStep(20)}},
+			},
+		},
+		{
+			name:     "nested message",
+			expected: appendUnknownBytes(nil, 20, nested),
+			actual:   appendUnknownBytes(nil, 20, appendUnknownVarint(nil, 1, 7)),
+			diffs: DiffErrors{
+				{Field: "#20", Message: "value mismatch", Expected: `#20(bytes)=<#1(varint)=7 #2(bytes)="x">`, Actual: `#20(bytes)=<#1(varint)=7>`, Path: Path{unknownFieldStep(20)}},
+			},
+		},
+		{
+			name:     "repeated number",
+			expected: appendUnknownVarint(appendUnknownVarint(nil, 17, 1), 17, 2),
+			actual:   appendUnknownVarint(nil, 17, 1),
+			diffs: DiffErrors{
+				{Field: "#17.[1]", Message: "missing field", Expected: "#17(varint)=2", Actual: "<nil>", Path: Path{unknownFieldStep(17), indexStep(1)}},
+			},
+		},
+		{
+			name:     "order mismatch",
+			expected: appendUnknownVarint(appendUnknownVarint(nil, 17, 42), 18, 1),
+			actual:   appendUnknownVarint(appendUnknownVarint(nil, 18, 1), 17, 42),
+			diffs: DiffErrors{
+				{Message: "order mismatch", Expected: "<#17(varint)=42 #18(varint)=1>", Actual: "<#18(varint)=1 #17(varint)=42>"},
+			},
+		},
+		{
+			name:     "unordered",
+			expected: appendUnknownVarint(appendUnknownVarint(nil, 17, 1), 17, 2),
+			actual:   appendUnknownVarint(appendUnknownVarint(nil, 17, 3), 17, 1),
+			opts:     []Option{UnorderedUnknown()},
+			diffs: DiffErrors{
+				{Field: "#17", Message: "missing field", Expected: "#17(varint)=2", Actual: "<nil>", Path: Path{unknownFieldStep(17)}},
+				{Field: "#17", Message: "unexpected field", Expected: "<nil>", Actual: "#17(varint)=3", Path: Path{unknownFieldStep(17)}},
+			},
+		},
+		{
+			name:     "partial",
+			expected: appendUnknownVarint(nil, 17, 42),
+			actual:   appendUnknownVarint(appendUnknownVarint(nil, 18, 1), 17, 42),
+			opts:     []Option{Partial()},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			expected := makeInput(nil)
+			expected.ProtoReflect().SetUnknown(tt.expected)
+			actual := makeInput(nil)
+			actual.ProtoReflect().SetUnknown(tt.actual)
+
+			diffs := withoutPaths(tt.diffs, EqualAll(expected, actual, tt.opts...))
+			if !reflect.DeepEqual(tt.diffs, diffs) {
+				t.Errorf("mismatch:\nexpected: %v\nactual:   %v", tt.diffs, diffs)
+			}
+		})
+	}
+}
+
+func TestFmtUnknown(t *testing.T) {
+	b := protowire.AppendTag(nil, 1, protowire.Fixed32Type)
+	b = protowire.AppendFixed32(b, 5)
+	b = protowire.AppendTag(b, 2, protowire.Fixed64Type)
+	b = protowire.AppendFixed64(b, 6)
+	b = appendUnknownBytes(b, 3, []byte{0xff, 0x00})
+	b = protowire.AppendTag(b, 4, protowire.StartGroupType)
+	b = appendUnknownVarint(b, 1, 1)
+	b = protowire.AppendTag(b, 4, protowire.EndGroupType)
+	b = append(b, 0xff)
+
+	expected := `<#1(fixed32)=5 #2(fixed64)=6 #3(bytes)="\xff\x00" #4(group)=<#1(varint)=1> #?(malformed)="\xff">`
+	if got := fmtUnknown(b); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
@@ -0,0 +1,67 @@
+package protocmp
+
+import (
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// wrapperFullNames are the well-known wrapper types in google/protobuf/wrappers.proto.
+var wrapperFullNames = map[protoreflect.FullName]bool{
+	"google.protobuf.DoubleValue": true,
+	"google.protobuf.FloatValue":  true,
+	"google.protobuf.Int64Value":  true,
+	"google.protobuf.UInt64Value": true,
+	"google.protobuf.Int32Value":  true,
+	"google.protobuf.UInt32Value": true,
+	"google.protobuf.BoolValue":   true,
+	"google.protobuf.StringValue": true,
+	"google.protobuf.BytesValue":  true,
+}
+
+// isWrapperMessage reports whether the message is one of the well-known
+// wrapper types such as google.protobuf.StringValue.
+func isWrapperMessage(md protoreflect.MessageDescriptor) bool {
+	return wrapperFullNames[md.FullName()]
+}
+
+// wrapperField returns the descriptor of the value field of a wrapper message.
+func wrapperField(md protoreflect.MessageDescriptor) protoreflect.FieldDescriptor {
+	return md.Fields().ByName("value")
+}
+
+// isZeroWrapper reports whether the message is a set wrapper holding the zero
+// value of its scalar.
+func isZeroWrapper(m protoreflect.Message) bool {
+	return m.IsValid() && !m.Has(wrapperField(m.Descriptor())) && len(m.GetUnknown()) == 0
+}
+
+// fmtWrapper renders a wrapper message as a nullable scalar, i.e. null if it
+// is unset or its quoted value otherwise.
+func fmtWrapper(m protoreflect.Message) string {
+	if !m.IsValid() {
+		return "null"
+	}
+	fd := wrapperField(m.Descriptor())
+	return fmtValue(m.Get(fd), fd)
+}
+
+// equalWrapper compares two wrapper messages as nullable scalars. An unset
+// wrapper only equals a wrapper holding the zero value with EquateWrapperZero
+// or EquateEmpty, or any wrapper on the expected side with Partial.
+func equalWrapper(opts *options, p fieldPath, mx, my protoreflect.Message) matchErrs {
+	if !mx.IsValid() && opts.partial {
+		return nil
+	}
+	if mx.IsValid() != my.IsValid() {
+		if (opts.equateWrapperZero || opts.equateEmpty) && (isZeroWrapper(mx) || isZeroWrapper(my)) {
+			return nil
+		}
+		return matchErrs{newMatchError("value mismatch").Values(fmtWrapper(mx), fmtWrapper(my))}
+	}
+	if !mx.IsValid() {
+		return nil
+	}
+
+	fd := wrapperField(mx.Descriptor())
+	errs := equalValue(opts, p, fd, mx.Get(fd), my.Get(fd))
+	return append(errs, equalUnknown(opts, mx.GetUnknown(), my.GetUnknown())...)
+}
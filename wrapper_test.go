@@ -0,0 +1,138 @@
+package protocmp
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/nbaztec/protocmp/protos/sample"
+)
+
+func TestAssertWrappers(t *testing.T) {
+	expected := makeInput(func(v *sample.Outer) {
+		v.WrappedString = wrapperspb.String("x")
+		v.WrappedInt = wrapperspb.Int64(0)
+	})
+
+	tests := []struct {
+		name  string
+		input *sample.Outer
+		opts  []Option
+		diff  *DiffError
+	}{
+		{
+			name:  "equal",
+			input: expected,
+		},
+		{
+			name: "value mismatch",
+			input: makeInput(func(v *sample.Outer) {
+				v.WrappedString = wrapperspb.String("y")
+				v.WrappedInt = wrapperspb.Int64(0)
+			}),
+			diff: &DiffError{
+				Field:    "wrapped_string",
+				Message:  "value mismatch",
+				Expected: `"x"`,
+				Actual:   `"y"`,
+			},
+		},
+		{
+			name: "missing",
+			input: makeInput(func(v *sample.Outer) {
+				v.WrappedInt = wrapperspb.Int64(0)
+			}),
+			diff: &DiffError{
+				Field:    "wrapped_string",
+				Message:  "value mismatch",
+				Expected: `"x"`,
+				Actual:   `null`,
+			},
+		},
+		{
+			name: "zero - unset",
+			input: makeInput(func(v *sample.Outer) {
+				v.WrappedString = wrapperspb.String("x")
+			}),
+			diff: &DiffError{
+				Field:    "wrapped_int",
+				Message:  "value mismatch",
+				Expected: `0`,
+				Actual:   `null`,
+			},
+		},
+		{
+			name: "zero - unset with EquateWrapperZero",
+			input: makeInput(func(v *sample.Outer) {
+				v.WrappedString = wrapperspb.String("x")
+			}),
+			opts: []Option{EquateWrapperZero()},
+		},
+		{
+			name: "empty string with EquateWrapperZero",
+			input: makeInput(func(v *sample.Outer) {
+				v.WrappedString = wrapperspb.String("")
+				v.WrappedInt = wrapperspb.Int64(0)
+			}),
+			opts: []Option{EquateWrapperZero()},
+			diff: &DiffError{
+				Field:    "wrapped_string",
+				Message:  "value mismatch",
+				Expected: `"x"`,
+				Actual:   `""`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			check(t, expected, tt.input, tt.diff, tt.opts...)
+		})
+	}
+}
+
+func TestWrapperComparer(t *testing.T) {
+	calls := 0
+	opt := Comparer("google.protobuf.StringValue", func(x, y protoreflect.Message) bool {
+		calls++
+		return true
+	})
+
+	expected := makeInput(func(v *sample.Outer) {
+		v.WrappedString = wrapperspb.String("x")
+	})
+	input := makeInput(func(v *sample.Outer) {
+		v.WrappedString = wrapperspb.String("y")
+	})
+	if err := Equal(expected, input, opt); err != nil {
+		t.Errorf("expected no difference, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected the comparer to be called once, got %d", calls)
+	}
+}
+
+func TestWrapperPartial(t *testing.T) {
+	var unset *wrapperspb.StringValue
+	opts := newOptions([]Option{Partial()})
+
+	if errs := equalWrapper(opts, nil, unset.ProtoReflect(), wrapperspb.String("x").ProtoReflect()); errs != nil {
+		t.Errorf("expected an unset expected wrapper to be skipped, got %v", errs.Diff())
+	}
+	if errs := equalWrapper(opts, nil, wrapperspb.String("x").ProtoReflect(), unset.ProtoReflect()); errs == nil {
+		t.Error("expected an unset actual wrapper to differ")
+	}
+}
+
+func TestFmtWrappers(t *testing.T) {
+	m := &sample.Outer{
+		WrappedString: wrapperspb.String("x"),
+		WrappedBool:   wrapperspb.Bool(false),
+	}
+	expected := `<wrapped_string:"x" wrapped_bool:false>`
+	if got := fmtMessage(m.ProtoReflect()); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}